@@ -0,0 +1,155 @@
+package atlas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const apiPath = "/api/atlas/v1.0"
+
+type Client struct {
+	ctx        context.Context
+	httpClient *http.Client
+	baseUrl    string
+}
+
+type errorData struct {
+	Detail    string `json:"detail"`
+	Error     int    `json:"error"`
+	ErrorCode string `json:"errorCode"`
+	Reason    string `json:"reason"`
+}
+
+// Error is returned for any response with an unexpected status code. The
+// Atlas error code and detail are included when the body can be decoded.
+type Error struct {
+	errors.DropboxError
+	StatusCode int
+	ErrorCode  string
+	Detail     string
+}
+
+func IsNotFound(err error) bool {
+	return IsStatus(err, 404)
+}
+
+func IsStatus(err error, statusCode int) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == statusCode
+}
+
+// NewClient returns a client for the Atlas API at baseUrl. The http client
+// is responsible for authentication, see digest.Transport.
+func NewClient(httpClient *http.Client, baseUrl string) *Client {
+	return &Client{
+		ctx:        context.Background(),
+		httpClient: httpClient,
+		baseUrl:    baseUrl,
+	}
+}
+
+// WithContext returns a copy of the client that sends requests with ctx,
+// requests and retries are abandoned once ctx is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clnt := *c
+	clnt.ctx = ctx
+	return &clnt
+}
+
+func (c *Client) do(method, path string, input, output interface{},
+	codes ...int) (err error) {
+
+	var body io.Reader
+	if input != nil {
+		data, e := json.Marshal(input)
+		if e != nil {
+			err = &errortypes.ParseError{
+				errors.Wrapf(e, "atlas: Marshal %s %s failed", method, path),
+			}
+			return
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseUrl+apiPath+path, body)
+	if err != nil {
+		err = &errortypes.RequestError{
+			errors.Wrapf(err, "atlas: Request %s %s failed", method, path),
+		}
+		return
+	}
+
+	req = req.WithContext(c.ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if input != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		err = &errortypes.RequestError{
+			errors.Wrapf(err, "atlas: Request %s %s failed", method, path),
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	expected := false
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			expected = true
+			break
+		}
+	}
+
+	if !expected {
+		err = parseError(method, path, resp)
+		return
+	}
+
+	if output != nil {
+		err = json.NewDecoder(resp.Body).Decode(output)
+		if err != nil {
+			err = &errortypes.ParseError{
+				errors.Wrapf(err, "atlas: Decode %s %s failed", method, path),
+			}
+			return
+		}
+	}
+
+	return
+}
+
+func parseError(method, path string, resp *http.Response) (err error) {
+	respBody, _ := ioutil.ReadAll(resp.Body)
+
+	data := &errorData{}
+	_ = json.Unmarshal(respBody, data)
+
+	msg := data.Detail
+	if msg == "" {
+		msg = string(respBody)
+	}
+
+	err = &Error{
+		DropboxError: errors.Newf(
+			"atlas: Request %s %s bad status %d %s",
+			method,
+			path,
+			resp.StatusCode,
+			msg,
+		),
+		StatusCode: resp.StatusCode,
+		ErrorCode:  data.ErrorCode,
+		Detail:     data.Detail,
+	}
+
+	return
+}
@@ -0,0 +1,315 @@
+package atlas
+
+import (
+	"fmt"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlastest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"net/http"
+	"testing"
+)
+
+func newTestClient(t *testing.T) (clnt *Client, srv *atlastest.Server,
+	groupId string) {
+
+	srv = atlastest.NewServer("user", "key")
+	srv.Transitions = 0
+
+	clnt = NewClient(
+		&http.Client{
+			Transport: &digest.Transport{
+				Username: srv.Username,
+				Password: srv.ApiKey,
+			},
+		},
+		srv.URL,
+	)
+
+	grp, err := clnt.CreateGroup(&GroupPost{
+		Name:  "test",
+		OrgId: "org",
+	})
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	groupId = grp.Id
+
+	return
+}
+
+func TestGroups(t *testing.T) {
+	clnt, srv, groupId := newTestClient(t)
+	defer srv.Close()
+
+	grp, err := clnt.GetGroup(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grp.Name != "test" {
+		t.Errorf("group name %s, expected test", grp.Name)
+	}
+
+	grp, err = clnt.GetGroupByName("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grp.Id != groupId {
+		t.Errorf("group id %s, expected %s", grp.Id, groupId)
+	}
+
+	err = clnt.DeleteGroup(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing objects are returned as nil without an error
+	grp, err = clnt.GetGroup(groupId)
+	if err != nil || grp != nil {
+		t.Errorf("get deleted group %v error %v, expected nil",
+			grp, err)
+	}
+
+	grp, err = clnt.GetGroupByName("test")
+	if err != nil || grp != nil {
+		t.Errorf("get deleted group %v error %v, expected nil",
+			grp, err)
+	}
+}
+
+func TestClusters(t *testing.T) {
+	clnt, srv, groupId := newTestClient(t)
+	defer srv.Close()
+
+	_, err := clnt.CreateCluster(groupId, &ClusterPost{
+		Name:        "test",
+		ClusterType: "REPLICASET",
+		DiskSizeGb:  10,
+		ProviderSettings: ClusterProvider{
+			ProviderName:     "AWS",
+			RegionName:       "US_EAST_1",
+			InstanceSizeName: "M10",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clst, err := clnt.GetCluster(groupId, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clst.Provider() != "AWS" || clst.Shared() {
+		t.Errorf("cluster provider %s shared %t, expected AWS false",
+			clst.Provider(), clst.Shared())
+	}
+
+	clsts, err := clnt.ListClusters(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clsts) != 1 || clsts[0].Name != "test" {
+		t.Errorf("listed %d clusters, expected test", len(clsts))
+	}
+
+	_, err = clnt.UpdateCluster(groupId, "test", &ClusterPut{
+		DiskSizeGb: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clst, err = clnt.GetCluster(groupId, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clst.DiskSizeGb != 20 {
+		t.Errorf("cluster disk %.0f, expected 20", clst.DiskSizeGb)
+	}
+
+	err = clnt.DeleteCluster(groupId, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		clst, err = clnt.GetCluster(groupId, "test")
+		if err != nil || clst == nil {
+			break
+		}
+	}
+	if err != nil || clst != nil {
+		t.Errorf("get deleted cluster %v error %v, expected nil",
+			clst, err)
+	}
+}
+
+func TestListContainers(t *testing.T) {
+	clnt, srv, groupId := newTestClient(t)
+	defer srv.Close()
+
+	for _, prvdr := range []ClusterProvider{
+		ClusterProvider{
+			ProviderName:     "AWS",
+			RegionName:       "US_EAST_1",
+			InstanceSizeName: "M10",
+		},
+		ClusterProvider{
+			ProviderName:     "GCP",
+			RegionName:       "CENTRAL_US",
+			InstanceSizeName: "M10",
+		},
+		ClusterProvider{
+			ProviderName:     "AZURE",
+			RegionName:       "US_EAST_2",
+			InstanceSizeName: "M10",
+		},
+	} {
+		_, err := clnt.CreateCluster(groupId, &ClusterPost{
+			Name:             prvdr.ProviderName,
+			ClusterType:      "REPLICASET",
+			ProviderSettings: prvdr,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, provider := range []string{"AWS", "GCP", "AZURE"} {
+		cntrs, err := clnt.ListContainers(groupId, provider)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(cntrs) != 1 || cntrs[0].ProviderName != provider {
+			t.Errorf("%s listed %d containers, expected 1",
+				provider, len(cntrs))
+		}
+	}
+}
+
+func TestDatabaseUsers(t *testing.T) {
+	clnt, srv, groupId := newTestClient(t)
+	defer srv.Close()
+
+	roles := []DatabaseUserRole{
+		DatabaseUserRole{
+			DatabaseName: "test",
+			RoleName:     "readWrite",
+		},
+	}
+
+	for _, authDatabase := range []string{"admin", "users"} {
+		_, err := clnt.CreateDatabaseUser(groupId, &DatabaseUserPost{
+			DatabaseName: authDatabase,
+			Username:     authDatabase + "-user",
+			Password:     "password",
+			GroupId:      groupId,
+			Roles:        roles,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usrs, err := clnt.ListDatabaseUsers(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usrs) != 2 {
+		t.Errorf("listed %d users, expected 2", len(usrs))
+	}
+
+	usr, err := clnt.GetDatabaseUser(groupId, "users", "users-user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usr.DatabaseName != "users" {
+		t.Errorf("user database %s, expected users", usr.DatabaseName)
+	}
+
+	usr, err = clnt.GetDatabaseUser(groupId, "admin", "users-user")
+	if err != nil || usr != nil {
+		t.Errorf("get user %v error %v, expected nil", usr, err)
+	}
+
+	roles[0].RoleName = "read"
+	_, err = clnt.UpdateDatabaseUser(groupId, "users", "users-user",
+		&DatabaseUserPut{
+			Password: "password2",
+			Roles:    roles,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usr, err = clnt.GetDatabaseUser(groupId, "users", "users-user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usr.Roles) != 1 || usr.Roles[0].RoleName != "read" {
+		t.Errorf("user roles %v, expected read", usr.Roles)
+	}
+
+	err = clnt.DeleteDatabaseUser(groupId, "users", "users-user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usr, err = clnt.GetDatabaseUser(groupId, "users", "users-user")
+	if err != nil || usr != nil {
+		t.Errorf("get deleted user %v error %v, expected nil", usr, err)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	clnt, srv, groupId := newTestClient(t)
+	defer srv.Close()
+
+	entries := []*WhitelistEntry{}
+	for i := 0; i < itemsPerPage+1; i++ {
+		entries = append(entries, &WhitelistEntry{
+			CidrBlock: fmt.Sprintf("10.0.%d.%d/32", i/256, i%256),
+		})
+	}
+
+	err := clnt.CreateWhitelistEntries(groupId, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := clnt.ListWhitelistEntries(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != len(entries) {
+		t.Errorf("listed %d entries, expected %d",
+			len(listed), len(entries))
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range listed {
+		if seen[entry.CidrBlock] {
+			t.Errorf("entry %s listed twice", entry.CidrBlock)
+		}
+		seen[entry.CidrBlock] = true
+	}
+}
+
+func TestError(t *testing.T) {
+	clnt, srv, _ := newTestClient(t)
+	defer srv.Close()
+
+	_, err := clnt.ListClusters("missing")
+	if !IsNotFound(err) {
+		t.Fatalf("list clusters error %v, expected not found", err)
+	}
+
+	e := err.(*Error)
+	if e.ErrorCode == "" {
+		t.Error("error code not set")
+	}
+	if IsStatus(nil, 404) {
+		t.Error("nil error has status 404")
+	}
+}
@@ -0,0 +1,290 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type ClusterComputeAutoScaling struct {
+	Enabled          bool `json:"enabled"`
+	ScaleDownEnabled bool `json:"scaleDownEnabled"`
+}
+
+type ClusterAutoScaling struct {
+	DiskGbEnabled bool                      `json:"diskGBEnabled"`
+	Compute       ClusterComputeAutoScaling `json:"compute"`
+}
+
+type ClusterProviderComputeAutoScaling struct {
+	MinInstanceSize string `json:"minInstanceSize,omitempty"`
+	MaxInstanceSize string `json:"maxInstanceSize,omitempty"`
+}
+
+type ClusterProviderAutoScaling struct {
+	Compute ClusterProviderComputeAutoScaling `json:"compute"`
+}
+
+type ClusterProvider struct {
+	ProviderName        string                      `json:"providerName"`
+	BackingProviderName string                      `json:"backingProviderName,omitempty"`
+	RegionName          string                      `json:"regionName,omitempty"`
+	InstanceSizeName    string                      `json:"instanceSizeName,omitempty"`
+	DiskIops            int                         `json:"diskIOPS,omitempty"`
+	VolumeType          string                      `json:"volumeType,omitempty"`
+	EncryptEbsVolume    *bool                       `json:"encryptEBSVolume,omitempty"`
+	DiskTypeName        string                      `json:"diskTypeName,omitempty"`
+	AutoScaling         *ClusterProviderAutoScaling `json:"autoScaling,omitempty"`
+}
+
+type ClusterRegionConfig struct {
+	ElectableNodes int `json:"electableNodes"`
+	Priority       int `json:"priority"`
+	ReadOnlyNodes  int `json:"readOnlyNodes"`
+	AnalyticsNodes int `json:"analyticsNodes"`
+}
+
+type ClusterReplicationSpec struct {
+	Id            string                         `json:"id,omitempty"`
+	NumShards     int                            `json:"numShards"`
+	ZoneName      string                         `json:"zoneName"`
+	RegionsConfig map[string]ClusterRegionConfig `json:"regionsConfig"`
+}
+
+type ClusterConnectionStrings struct {
+	Standard    string `json:"standard"`
+	StandardSrv string `json:"standardSrv"`
+	Private     string `json:"private"`
+	PrivateSrv  string `json:"privateSrv"`
+}
+
+type ClusterPost struct {
+	AutoScaling           ClusterAutoScaling        `json:"autoScaling"`
+	Name                  string                    `json:"name"`
+	MongoDbMajorVersion   string                    `json:"mongoDBMajorVersion"`
+	ClusterType           string                    `json:"clusterType"`
+	NumShards             int                       `json:"numShards,omitempty"`
+	ReplicationFactor     int                       `json:"replicationFactor,omitempty"`
+	ReplicationSpecs      []*ClusterReplicationSpec `json:"replicationSpecs,omitempty"`
+	BackupEnabled         bool                      `json:"backupEnabled"`
+	ProviderBackupEnabled bool                      `json:"providerBackupEnabled"`
+	DiskSizeGb            int                       `json:"diskSizeGB,omitempty"`
+	ProviderSettings      ClusterProvider           `json:"providerSettings"`
+	TerminationProtection bool                      `json:"terminationProtectionEnabled"`
+}
+
+// ClusterPut holds the changed fields of a cluster, fields that are not
+// set are left unchanged by Atlas.
+type ClusterPut struct {
+	AutoScaling           *ClusterAutoScaling       `json:"autoScaling,omitempty"`
+	MongoDbMajorVersion   string                    `json:"mongoDBMajorVersion,omitempty"`
+	ClusterType           string                    `json:"clusterType,omitempty"`
+	NumShards             int                       `json:"numShards,omitempty"`
+	ReplicationFactor     int                       `json:"replicationFactor,omitempty"`
+	ReplicationSpecs      []*ClusterReplicationSpec `json:"replicationSpecs,omitempty"`
+	BackupEnabled         *bool                     `json:"backupEnabled,omitempty"`
+	ProviderBackupEnabled *bool                     `json:"providerBackupEnabled,omitempty"`
+	DiskSizeGb            int                       `json:"diskSizeGB,omitempty"`
+	ProviderSettings      *ClusterProvider          `json:"providerSettings,omitempty"`
+	TerminationProtection *bool                     `json:"terminationProtectionEnabled,omitempty"`
+}
+
+type ClusterPause struct {
+	Paused bool `json:"paused"`
+}
+
+type Cluster struct {
+	Id                    string                    `json:"id"`
+	Name                  string                    `json:"name"`
+	GroupId               string                    `json:"groupId"`
+	ClusterType           string                    `json:"clusterType"`
+	StateName             string                    `json:"stateName"`
+	MongoUri              string                    `json:"mongoURI"`
+	MongoUriWithOptions   string                    `json:"mongoURIWithOptions"`
+	SrvAddress            string                    `json:"srvAddress"`
+	MongoDbVersion        string                    `json:"mongoDBVersion"`
+	MongoDbMajorVersion   string                    `json:"mongoDBMajorVersion"`
+	NumShards             int                       `json:"numShards"`
+	ReplicationFactor     int                       `json:"replicationFactor"`
+	ReplicationSpecs      []*ClusterReplicationSpec `json:"replicationSpecs"`
+	DiskSizeGb            float64                   `json:"diskSizeGB"`
+	BackupEnabled         bool                      `json:"backupEnabled"`
+	ProviderBackupEnabled bool                      `json:"providerBackupEnabled"`
+	Paused                bool                      `json:"paused"`
+	AutoScaling           ClusterAutoScaling        `json:"autoScaling"`
+	ProviderSettings      ClusterProvider           `json:"providerSettings"`
+	TerminationProtection bool                      `json:"terminationProtectionEnabled"`
+	ConnectionStrings     ClusterConnectionStrings  `json:"connectionStrings"`
+}
+
+// Provider returns the cloud provider of the cluster, shared tier clusters
+// use the TENANT provider backed by a cloud provider.
+func (c *Cluster) Provider() string {
+	if c.ProviderSettings.ProviderName == "TENANT" {
+		return c.ProviderSettings.BackingProviderName
+	}
+	return c.ProviderSettings.ProviderName
+}
+
+func (c *Cluster) Shared() bool {
+	return c.ProviderSettings.ProviderName == "TENANT"
+}
+
+func (c *Cluster) Available() bool {
+	switch c.StateName {
+	case "IDLE", "REPAIRING":
+		return true
+	case "CREATING", "UPDATING", "DELETING", "DELETED":
+		return false
+	default:
+		return c.Paused
+	}
+}
+
+func (c *Cluster) Deleted() bool {
+	return c.StateName == "DELETED"
+}
+
+func (c *Cluster) Deleting() bool {
+	switch c.StateName {
+	case "DELETING", "DELETED":
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Cluster) Updating() bool {
+	switch c.StateName {
+	case "UPDATING", "REPAIRING":
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) GetCluster(groupId, name string) (data *Cluster, err error) {
+	data = &Cluster{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf("/groups/%s/clusters/%s", groupId, name),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) ListClusters(groupId string) (
+	data []*Cluster, err error) {
+
+	data = []*Cluster{}
+	err = c.list(
+		fmt.Sprintf("/groups/%s/clusters", groupId),
+		func(result json.RawMessage) (err error) {
+			clst := &Cluster{}
+			err = json.Unmarshal(result, clst)
+			if err != nil {
+				return
+			}
+			data = append(data, clst)
+			return
+		},
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) CreateCluster(groupId string, input *ClusterPost) (
+	data *Cluster, err error) {
+
+	data = &Cluster{}
+	err = c.do(
+		"POST",
+		fmt.Sprintf("/groups/%s/clusters", groupId),
+		input,
+		data,
+		201,
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) UpdateCluster(groupId, name string, input *ClusterPut) (
+	data *Cluster, err error) {
+
+	data = &Cluster{}
+	err = c.do(
+		"PATCH",
+		fmt.Sprintf("/groups/%s/clusters/%s", groupId, name),
+		input,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) PauseCluster(groupId, name string, paused bool) (
+	data *Cluster, err error) {
+
+	data = &Cluster{}
+	err = c.do(
+		"PATCH",
+		fmt.Sprintf("/groups/%s/clusters/%s", groupId, name),
+		&ClusterPause{
+			Paused: paused,
+		},
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) DeleteCluster(groupId, name string) (err error) {
+	err = c.do(
+		"DELETE",
+		fmt.Sprintf("/groups/%s/clusters/%s", groupId, name),
+		nil,
+		nil,
+		200, 202, 204,
+	)
+	if err != nil {
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
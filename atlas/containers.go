@@ -0,0 +1,79 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type Container struct {
+	Id                  string   `json:"id"`
+	ProviderName        string   `json:"providerName"`
+	RegionName          string   `json:"regionName"`
+	Region              string   `json:"region"`
+	Regions             []string `json:"regions"`
+	VpcId               string   `json:"vpcId"`
+	NetworkName         string   `json:"networkName"`
+	GcpProjectId        string   `json:"gcpProjectId"`
+	VnetName            string   `json:"vnetName"`
+	AzureSubscriptionId string   `json:"azureSubscriptionId"`
+	AtlasCidrBlock      string   `json:"atlasCidrBlock"`
+	Provisioned         bool     `json:"provisioned"`
+}
+
+// Matches returns true when the container holds clusters in the region.
+// AWS containers are regional, Azure containers use the region field and
+// GCP containers are global to the group.
+func (c *Container) Matches(providerName, regionName string) bool {
+	if c.ProviderName != "" && c.ProviderName != providerName {
+		return false
+	}
+
+	switch providerName {
+	case "GCP":
+		return true
+	case "AZURE":
+		return c.Region == regionName
+	default:
+		return c.RegionName == regionName
+	}
+}
+
+// NetworkId returns the id of the provider network of the container.
+func (c *Container) NetworkId() string {
+	switch c.ProviderName {
+	case "GCP":
+		return c.NetworkName
+	case "AZURE":
+		return c.VnetName
+	default:
+		return c.VpcId
+	}
+}
+
+// ListContainers returns the containers of a provider, Atlas only returns
+// AWS containers when the provider is not specified.
+func (c *Client) ListContainers(groupId, providerName string) (
+	data []*Container, err error) {
+
+	data = []*Container{}
+	err = c.list(
+		fmt.Sprintf("/groups/%s/containers?providerName=%s", groupId,
+			url.QueryEscape(providerName)),
+		func(result json.RawMessage) (err error) {
+			cntr := &Container{}
+			err = json.Unmarshal(result, cntr)
+			if err != nil {
+				return
+			}
+			data = append(data, cntr)
+			return
+		},
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
@@ -0,0 +1,86 @@
+package atlas
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type Group struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	OrgId string `json:"orgId"`
+}
+
+type GroupPost struct {
+	Name  string `json:"name"`
+	OrgId string `json:"orgId"`
+}
+
+func (c *Client) GetGroupByName(name string) (data *Group, err error) {
+	data = &Group{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf("/groups/byName/%s", url.PathEscape(name)),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) || IsStatus(err, 401) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) GetGroup(groupId string) (data *Group, err error) {
+	data = &Group{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf("/groups/%s", groupId),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) CreateGroup(input *GroupPost) (data *Group, err error) {
+	data = &Group{}
+	err = c.do("POST", "/groups", input, data, 201)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) DeleteGroup(groupId string) (err error) {
+	err = c.do(
+		"DELETE",
+		fmt.Sprintf("/groups/%s", groupId),
+		nil,
+		nil,
+		200, 202, 204,
+	)
+	if err != nil {
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
@@ -0,0 +1,65 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"strings"
+)
+
+const itemsPerPage = 500
+
+type listResp struct {
+	Results    []json.RawMessage `json:"results"`
+	TotalCount int               `json:"totalCount"`
+}
+
+// list requests every page of a list endpoint and decodes each result
+// using the provided function. The path may include a query string.
+func (c *Client) list(path string,
+	decode func(data json.RawMessage) error) (err error) {
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	count := 0
+	for pageNum := 1; ; pageNum++ {
+		respData := &listResp{}
+		err = c.do(
+			"GET",
+			fmt.Sprintf(
+				"%s%spageNum=%d&itemsPerPage=%d",
+				path,
+				sep,
+				pageNum,
+				itemsPerPage,
+			),
+			nil,
+			respData,
+			200,
+		)
+		if err != nil {
+			return
+		}
+
+		for _, result := range respData.Results {
+			err = decode(result)
+			if err != nil {
+				err = &errortypes.ParseError{
+					errors.Wrapf(err, "atlas: Decode GET %s failed", path),
+				}
+				return
+			}
+		}
+
+		count += len(respData.Results)
+		if len(respData.Results) == 0 || count >= respData.TotalCount {
+			break
+		}
+	}
+
+	return
+}
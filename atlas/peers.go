@@ -0,0 +1,151 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type PeerPost struct {
+	VpcId               string `json:"vpcId"`
+	AwsAccountId        string `json:"awsAccountId"`
+	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
+	ContainerId         string `json:"containerId"`
+}
+
+type PeerPut struct {
+	VpcId               string `json:"vpcId"`
+	AwsAccountId        string `json:"awsAccountId"`
+	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
+	ContainerId         string `json:"containerId"`
+}
+
+type Peer struct {
+	Id                  string `json:"id"`
+	VpcId               string `json:"vpcId"`
+	AwsAccountId        string `json:"awsAccountId"`
+	ConnectionId        string `json:"connectionId"`
+	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
+	ContainerId         string `json:"containerId"`
+	StatusName          string `json:"statusName"`
+	ErrorStateName      string `json:"errorStateName"`
+}
+
+func (p *Peer) Available() bool {
+	switch p.StatusName {
+	case "PENDING_ACCEPTANCE", "FINALIZING", "AVAILABLE":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Peer) Failed() bool {
+	switch p.StatusName {
+	case "FAILED", "TERMINATING":
+		return true
+	}
+
+	if p.ErrorStateName != "" {
+		return true
+	}
+
+	return false
+}
+
+func (c *Client) ListPeers(groupId string) (data []*Peer, err error) {
+	data = []*Peer{}
+	err = c.list(
+		fmt.Sprintf("/groups/%s/peers", groupId),
+		func(result json.RawMessage) (err error) {
+			pr := &Peer{}
+			err = json.Unmarshal(result, pr)
+			if err != nil {
+				return
+			}
+			data = append(data, pr)
+			return
+		},
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) GetPeer(groupId, peerId string) (data *Peer, err error) {
+	data = &Peer{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf("/groups/%s/peers/%s", groupId, peerId),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) CreatePeer(groupId string, input *PeerPost) (
+	data *Peer, err error) {
+
+	data = &Peer{}
+	err = c.do(
+		"POST",
+		fmt.Sprintf("/groups/%s/peers", groupId),
+		input,
+		data,
+		201,
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) UpdatePeer(groupId, peerId string, input *PeerPut) (
+	data *Peer, err error) {
+
+	data = &Peer{}
+	err = c.do(
+		"PATCH",
+		fmt.Sprintf("/groups/%s/peers/%s", groupId, peerId),
+		input,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) DeletePeer(groupId, peerId string) (err error) {
+	err = c.do(
+		"DELETE",
+		fmt.Sprintf("/groups/%s/peers/%s", groupId, peerId),
+		nil,
+		nil,
+		200, 202, 204,
+	)
+	if err != nil {
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
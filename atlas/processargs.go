@@ -0,0 +1,56 @@
+package atlas
+
+import (
+	"fmt"
+)
+
+type ProcessArgs struct {
+	FailIndexKeyTooLong       bool   `json:"failIndexKeyTooLong"`
+	JavascriptEnabled         bool   `json:"javascriptEnabled"`
+	MinimumEnabledTlsProtocol string `json:"minimumEnabledTlsProtocol,omitempty"`
+	NoTableScan               bool   `json:"noTableScan"`
+	OplogSizeMb               int    `json:"oplogSizeMB,omitempty"`
+	DefaultReadConcern        string `json:"defaultReadConcern,omitempty"`
+	DefaultWriteConcern       string `json:"defaultWriteConcern,omitempty"`
+}
+
+func (c *Client) GetProcessArgs(groupId, name string) (
+	data *ProcessArgs, err error) {
+
+	data = &ProcessArgs{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf("/groups/%s/clusters/%s/processArgs", groupId, name),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) UpdateProcessArgs(groupId, name string,
+	input *ProcessArgs) (data *ProcessArgs, err error) {
+
+	data = &ProcessArgs{}
+	err = c.do(
+		"PATCH",
+		fmt.Sprintf("/groups/%s/clusters/%s/processArgs", groupId, name),
+		input,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
@@ -0,0 +1,155 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type DatabaseUserRole struct {
+	DatabaseName   string `json:"databaseName"`
+	CollectionName string `json:"collectionName,omitempty"`
+	RoleName       string `json:"roleName"`
+}
+
+type DatabaseUser struct {
+	DatabaseName string             `json:"databaseName"`
+	Username     string             `json:"username"`
+	GroupId      string             `json:"groupId"`
+	Roles        []DatabaseUserRole `json:"roles"`
+}
+
+type DatabaseUserPost struct {
+	DatabaseName string             `json:"databaseName"`
+	Username     string             `json:"username"`
+	Password     string             `json:"password"`
+	GroupId      string             `json:"groupId"`
+	Roles        []DatabaseUserRole `json:"roles"`
+}
+
+type DatabaseUserPut struct {
+	Password string             `json:"password"`
+	Roles    []DatabaseUserRole `json:"roles"`
+}
+
+func (c *Client) GetDatabaseUser(groupId, databaseName, username string) (
+	data *DatabaseUser, err error) {
+
+	data = &DatabaseUser{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf(
+			"/groups/%s/databaseUsers/%s/%s",
+			groupId,
+			url.PathEscape(databaseName),
+			url.PathEscape(username),
+		),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) ListDatabaseUsers(groupId string) (
+	data []*DatabaseUser, err error) {
+
+	data = []*DatabaseUser{}
+	err = c.list(
+		fmt.Sprintf("/groups/%s/databaseUsers", groupId),
+		func(result json.RawMessage) (err error) {
+			usr := &DatabaseUser{}
+			err = json.Unmarshal(result, usr)
+			if err != nil {
+				return
+			}
+			data = append(data, usr)
+			return
+		},
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) CreateDatabaseUser(groupId string,
+	input *DatabaseUserPost) (data *DatabaseUser, err error) {
+
+	data = &DatabaseUser{}
+	err = c.do(
+		"POST",
+		fmt.Sprintf("/groups/%s/databaseUsers", groupId),
+		input,
+		data,
+		201,
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) UpdateDatabaseUser(groupId, databaseName, username string,
+	input *DatabaseUserPut) (data *DatabaseUser, err error) {
+
+	data = &DatabaseUser{}
+	err = c.do(
+		"PATCH",
+		fmt.Sprintf(
+			"/groups/%s/databaseUsers/%s/%s",
+			groupId,
+			url.PathEscape(databaseName),
+			url.PathEscape(username),
+		),
+		input,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) DeleteDatabaseUser(groupId, databaseName,
+	username string) (err error) {
+
+	err = c.do(
+		"DELETE",
+		fmt.Sprintf(
+			"/groups/%s/databaseUsers/%s/%s",
+			groupId,
+			url.PathEscape(databaseName),
+			url.PathEscape(username),
+		),
+		nil,
+		nil,
+		200, 202, 204,
+	)
+	if err != nil {
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
@@ -0,0 +1,103 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type WhitelistEntry struct {
+	CidrBlock string `json:"cidrBlock,omitempty"`
+	IpAddress string `json:"ipAddress,omitempty"`
+	GroupId   string `json:"groupId,omitempty"`
+	Comment   string `json:"comment"`
+}
+
+func (c *Client) ListWhitelistEntries(groupId string) (
+	data []*WhitelistEntry, err error) {
+
+	data = []*WhitelistEntry{}
+	err = c.list(
+		fmt.Sprintf("/groups/%s/whitelist", groupId),
+		func(result json.RawMessage) (err error) {
+			entry := &WhitelistEntry{}
+			err = json.Unmarshal(result, entry)
+			if err != nil {
+				return
+			}
+			data = append(data, entry)
+			return
+		},
+	)
+	if err != nil {
+		data = nil
+		return
+	}
+
+	return
+}
+
+func (c *Client) GetWhitelistEntry(groupId, address string) (
+	data *WhitelistEntry, err error) {
+
+	data = &WhitelistEntry{}
+	err = c.do(
+		"GET",
+		fmt.Sprintf(
+			"/groups/%s/whitelist/%s",
+			groupId,
+			url.QueryEscape(address),
+		),
+		nil,
+		data,
+		200,
+	)
+	if err != nil {
+		data = nil
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (c *Client) CreateWhitelistEntries(groupId string,
+	input []*WhitelistEntry) (err error) {
+
+	err = c.do(
+		"POST",
+		fmt.Sprintf("/groups/%s/whitelist", groupId),
+		input,
+		nil,
+		201,
+	)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (c *Client) DeleteWhitelistEntry(groupId, address string) (err error) {
+	err = c.do(
+		"DELETE",
+		fmt.Sprintf(
+			"/groups/%s/whitelist/%s",
+			groupId,
+			url.QueryEscape(address),
+		),
+		nil,
+		nil,
+		200, 202, 204,
+	)
+	if err != nil {
+		if IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
@@ -0,0 +1,163 @@
+package atlastest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const realm = "MMS Public API"
+
+type nonce struct {
+	uses  int
+	count uint64
+}
+
+func (s *Server) challenge(w http.ResponseWriter, stale bool) {
+	value := newId()
+	s.nonces[value] = &nonce{}
+
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+		`Digest realm="%s", domain="", nonce="%s", opaque="%s", `+
+			`algorithm=%s, qop="auth", stale=%t`,
+		realm,
+		value,
+		s.opaque,
+		s.Algorithm,
+		stale,
+	))
+	writeError(w, 401, "", "You are not authorized for this resource.")
+}
+
+// authenticate validates the digest Authorization header. Requests must use
+// a nonce issued by the server with an increasing nonce count, echo the
+// opaque value and sign the full request URI including the query string.
+// Nonces are reported as stale once used NonceLimit times.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Digest ") {
+		s.challenge(w, false)
+		return false
+	}
+
+	params := parseParams(strings.TrimPrefix(header, "Digest "))
+
+	nonceVal := s.nonces[params["nonce"]]
+	if nonceVal == nil {
+		s.challenge(w, false)
+		return false
+	}
+
+	count, err := strconv.ParseUint(params["nc"], 16, 64)
+	if err != nil || count <= nonceVal.count {
+		s.challenge(w, false)
+		return false
+	}
+
+	if params["username"] != s.Username || params["realm"] != realm ||
+		params["opaque"] != s.opaque || params["qop"] != "auth" ||
+		params["uri"] != r.URL.RequestURI() ||
+		!strings.EqualFold(params["algorithm"], s.Algorithm) {
+
+		s.challenge(w, false)
+		return false
+	}
+
+	ha1 := s.hash(fmt.Sprintf("%s:%s:%s", s.Username, realm, s.ApiKey))
+	if strings.HasSuffix(strings.ToUpper(s.Algorithm), "-SESS") {
+		ha1 = s.hash(fmt.Sprintf(
+			"%s:%s:%s", ha1, params["nonce"], params["cnonce"]))
+	}
+	ha2 := s.hash(fmt.Sprintf("%s:%s", r.Method, params["uri"]))
+	expected := s.hash(fmt.Sprintf(
+		"%s:%s:%s:%s:%s:%s",
+		ha1,
+		params["nonce"],
+		params["nc"],
+		params["cnonce"],
+		params["qop"],
+		ha2,
+	))
+
+	if params["response"] != expected {
+		s.challenge(w, false)
+		return false
+	}
+
+	if s.NonceLimit > 0 && nonceVal.uses >= s.NonceLimit {
+		delete(s.nonces, params["nonce"])
+		s.challenge(w, true)
+		return false
+	}
+
+	nonceVal.uses += 1
+	nonceVal.count = count
+
+	return true
+}
+
+func (s *Server) hash(input string) string {
+	var hsh hash.Hash
+	if strings.HasPrefix(strings.ToUpper(s.Algorithm), "SHA-256") {
+		hsh = sha256.New()
+	} else {
+		hsh = md5.New()
+	}
+
+	hsh.Write([]byte(input))
+
+	return fmt.Sprintf("%x", hsh.Sum(nil))
+}
+
+// parseParams parses a comma separated list of key=value pairs where values
+// may be quoted strings containing commas, equal signs and escapes.
+func parseParams(input string) (params map[string]string) {
+	params = map[string]string{}
+
+	for len(input) > 0 {
+		input = strings.TrimLeft(input, " ,")
+		eq := strings.IndexByte(input, '=')
+		if eq < 0 {
+			return
+		}
+
+		key := strings.ToLower(strings.TrimSpace(input[:eq]))
+		input = strings.TrimLeft(input[eq+1:], " ")
+
+		val := ""
+		if strings.HasPrefix(input, `"`) {
+			buf := []byte{}
+			i := 1
+			for ; i < len(input); i++ {
+				if input[i] == '\\' && i+1 < len(input) {
+					i += 1
+					buf = append(buf, input[i])
+				} else if input[i] == '"' {
+					break
+				} else {
+					buf = append(buf, input[i])
+				}
+			}
+			val = string(buf)
+			if i < len(input) {
+				i += 1
+			}
+			input = input[i:]
+		} else {
+			end := strings.IndexByte(input, ',')
+			if end < 0 {
+				end = len(input)
+			}
+			val = strings.TrimSpace(input[:end])
+			input = input[end:]
+		}
+
+		params[key] = val
+	}
+
+	return
+}
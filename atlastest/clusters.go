@@ -0,0 +1,309 @@
+package atlastest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+func (s *Server) routeClusters(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	segs := r.segments
+
+	if len(segs) == 3 {
+		switch r.Method {
+		case "GET":
+			s.listClusters(w, r, grp)
+		case "POST":
+			s.createCluster(w, r, grp)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	name := segs[3]
+	clst := grp.clusters[name]
+	if clst == nil {
+		writeNotFound(w, "CLUSTER_NOT_FOUND", "cluster", name)
+		return
+	}
+
+	if len(segs) == 5 && segs[4] == "processArgs" {
+		s.routeProcessArgs(w, r, grp, name, clst)
+		return
+	}
+
+	if len(segs) != 4 {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		if clst.read(s.Transitions) {
+			delete(grp.clusters, name)
+			delete(grp.processArgs, name)
+			writeNotFound(w, "CLUSTER_NOT_FOUND", "cluster", name)
+			return
+		}
+		writeJson(w, 200, clst.doc)
+	case "PATCH":
+		s.updateCluster(w, r, grp, clst)
+	case "DELETE":
+		if protected, _ := clst.doc["terminationProtectionEnabled"].(bool); protected {
+
+			writeError(w, 400, "CANNOT_TERMINATE_CLUSTER_WHEN_"+
+				"TERMINATION_PROTECTION_ENABLED",
+				"Termination protection is enabled for cluster "+name+".")
+			return
+		}
+		// Atlas lists deleted clusters for a while before removing them
+		clst.transition("DELETING", "DELETED", "")
+		writeJson(w, 202, document{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	names := []string{}
+	for name := range grp.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := []interface{}{}
+	for _, name := range names {
+		items = append(items, grp.clusters[name].doc)
+	}
+
+	writeList(w, r, items)
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	name, _ := r.input["name"].(string)
+	if name == "" {
+		writeError(w, 400, "INVALID_ATTRIBUTE", "Invalid attribute name")
+		return
+	}
+
+	if grp.clusters[name] != nil {
+		writeError(w, 400, "DUPLICATE_CLUSTER_NAME",
+			"A cluster named "+name+" is already present in this group.")
+		return
+	}
+
+	host := fmt.Sprintf("%s-%s", name, newId()[:5])
+
+	doc := document{
+		"id":                newId(),
+		"groupId":           grp.doc["id"],
+		"clusterType":       "REPLICASET",
+		"numShards":         1,
+		"replicationFactor": 3,
+		"diskSizeGB":        10,
+		"backupEnabled":     false,
+		"paused":            false,
+		"srvAddress":        fmt.Sprintf("mongodb+srv://%s.mongodb.net", host),
+	}
+	merge(doc, r.input)
+	setClusterUris(doc)
+
+	// Atlas sets the AWS volume defaults and encrypts volumes unless
+	// encryption is disabled
+	prvdr, _ := doc["providerSettings"].(map[string]interface{})
+	if prvdr != nil && prvdr["providerName"] == "AWS" {
+		for key, val := range map[string]interface{}{
+			"diskIOPS":         100,
+			"volumeType":       "STANDARD",
+			"encryptEBSVolume": true,
+		} {
+			if _, ok := prvdr[key]; !ok {
+				prvdr[key] = val
+			}
+		}
+	}
+
+	if version, ok := doc["mongoDBMajorVersion"].(string); ok {
+		doc["mongoDBVersion"] = version + ".0"
+	}
+
+	clst := &object{
+		doc:      doc,
+		stateKey: "stateName",
+	}
+	clst.transition("CREATING", "IDLE")
+	grp.clusters[name] = clst
+	grp.processArgs[name] = document{
+		"failIndexKeyTooLong":       true,
+		"javascriptEnabled":         true,
+		"minimumEnabledTlsProtocol": "TLS1_2",
+		"noTableScan":               false,
+		"defaultReadConcern":        "available",
+		"defaultWriteConcern":       "1",
+	}
+
+	grp.ensureClusterContainers(doc)
+
+	writeJson(w, 201, doc)
+}
+
+func (s *Server) routeProcessArgs(w http.ResponseWriter, r *request,
+	grp *group, name string, clst *object) {
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, 200, grp.processArgs[name])
+	case "PATCH":
+		if clst.state() == "DELETING" {
+			writeError(w, 400, "CLUSTER_ALREADY_REQUESTED_DELETION",
+				"The cluster has already been requested for deletion.")
+			return
+		}
+		if paused, _ := clst.doc["paused"].(bool); paused {
+			writeError(w, 400, "CANNOT_UPDATE_PAUSED_CLUSTER",
+				"Cannot update cluster while it is paused or being "+
+					"paused.")
+			return
+		}
+
+		merge(grp.processArgs[name], r.input)
+		clst.transition("UPDATING", "IDLE")
+
+		writeJson(w, 200, grp.processArgs[name])
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// setClusterUris sets the connection strings with three hosts for each
+// shard named from the srvAddress host as Atlas does.
+func setClusterUris(doc document) {
+	host := strings.TrimSuffix(strings.TrimPrefix(
+		doc["srvAddress"].(string), "mongodb+srv://"), ".mongodb.net")
+
+	numShards := 1
+	switch n := doc["numShards"].(type) {
+	case int:
+		numShards = n
+	case float64:
+		numShards = int(n)
+	}
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	hosts := []string{}
+	privateHosts := []string{}
+	for i := 0; i < numShards; i++ {
+		for j := 0; j < 3; j++ {
+			hosts = append(hosts, fmt.Sprintf(
+				"%s-shard-%02d-%02d.mongodb.net:27017", host, i, j))
+			privateHosts = append(privateHosts, fmt.Sprintf(
+				"%s-shard-%02d-%02d-pri.mongodb.net:27017", host, i, j))
+		}
+	}
+
+	uri := "mongodb://" + strings.Join(hosts, ",")
+	options := "/?ssl=true&authSource=admin"
+	if doc["clusterType"] == "REPLICASET" {
+		options += fmt.Sprintf("&replicaSet=%s-shard-0", host)
+	}
+
+	doc["mongoURI"] = uri
+	doc["mongoURIWithOptions"] = uri + options
+	doc["connectionStrings"] = document{
+		"standard":    uri,
+		"standardSrv": doc["srvAddress"],
+		"private":     "mongodb://" + strings.Join(privateHosts, ","),
+		"privateSrv": fmt.Sprintf(
+			"mongodb+srv://%s-pri.mongodb.net", host),
+	}
+}
+
+// ensureClusterContainers assigns ids to new replication specs and
+// provisions the containers for every region of the cluster.
+func (g *group) ensureClusterContainers(doc document) {
+	prvdr, _ := doc["providerSettings"].(map[string]interface{})
+	if prvdr == nil {
+		return
+	}
+	providerName, _ := prvdr["providerName"].(string)
+
+	if regionName, _ := prvdr["regionName"].(string); regionName != "" {
+		g.ensureContainer(providerName, regionName)
+	}
+
+	specs, _ := doc["replicationSpecs"].([]interface{})
+	for _, specInf := range specs {
+		spec, _ := specInf.(map[string]interface{})
+		if spec == nil {
+			continue
+		}
+
+		if id, _ := spec["id"].(string); id == "" {
+			spec["id"] = newId()
+		}
+
+		regions, _ := spec["regionsConfig"].(map[string]interface{})
+		for regionName := range regions {
+			g.ensureContainer(providerName, regionName)
+		}
+	}
+}
+
+func (s *Server) updateCluster(w http.ResponseWriter, r *request,
+	grp *group, clst *object) {
+
+	if clst.state() == "DELETING" {
+		writeError(w, 400, "CLUSTER_ALREADY_REQUESTED_DELETION",
+			"The cluster has already been requested for deletion.")
+		return
+	}
+
+	paused, _ := clst.doc["paused"].(bool)
+	pause, pauseOk := r.input["paused"].(bool)
+	if pauseOk && len(r.input) != 1 {
+		writeError(w, 400, "CANNOT_PAUSE_AND_UPDATE_CLUSTER",
+			"Cannot pause or resume a cluster and update it in the same "+
+				"request.")
+		return
+	}
+	if paused && !pauseOk {
+		writeError(w, 400, "CANNOT_UPDATE_PAUSED_CLUSTER",
+			"Cannot update cluster while it is paused or being paused.")
+		return
+	}
+	if pause {
+		prvdr, _ := clst.doc["providerSettings"].(map[string]interface{})
+		if prvdr != nil && prvdr["providerName"] == "TENANT" {
+			writeError(w, 400, "CANNOT_PAUSE_TENANT_CLUSTER",
+				"Shared tier clusters cannot be paused.")
+			return
+		}
+	}
+
+	merge(clst.doc, r.input)
+	setClusterUris(clst.doc)
+
+	prvdr, _ := clst.doc["providerSettings"].(map[string]interface{})
+	if prvdr != nil && prvdr["providerName"] != "TENANT" {
+		delete(prvdr, "backingProviderName")
+	}
+
+	if version, ok := r.input["mongoDBMajorVersion"].(string); ok {
+		clst.doc["mongoDBVersion"] = version + ".0"
+	}
+
+	grp.ensureClusterContainers(clst.doc)
+	clst.transition("UPDATING", "IDLE")
+
+	writeJson(w, 200, clst.doc)
+}
@@ -0,0 +1,116 @@
+package atlastest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+func (s *Server) routeContainers(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	segs := r.segments
+
+	if len(segs) == 3 {
+		if r.Method != "GET" {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		// Atlas lists only AWS containers without a provider
+		provider := r.URL.Query().Get("providerName")
+		if provider == "" {
+			provider = "AWS"
+		}
+
+		ids := []string{}
+		for id, cntr := range grp.containers {
+			if cntr["providerName"] == provider {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		items := []interface{}{}
+		for _, id := range ids {
+			items = append(items, grp.containers[id])
+		}
+
+		writeList(w, r, items)
+		return
+	}
+
+	cntr := grp.containers[segs[3]]
+	if cntr == nil || len(segs) != 4 {
+		writeNotFound(w, "CLOUD_PROVIDER_CONTAINER_NOT_FOUND",
+			"container", segs[3])
+		return
+	}
+
+	if r.Method != "GET" {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	writeJson(w, 200, cntr)
+}
+
+// ensureContainer provisions the network container for a provider region
+// the first time a cluster is created in it, as Atlas does. GCP containers
+// are global and Azure containers store the region in the region field.
+func (g *group) ensureContainer(providerName, regionName string) {
+	if providerName == "" || providerName == "TENANT" {
+		return
+	}
+
+	for _, cntr := range g.containers {
+		if cntr["providerName"] != providerName {
+			continue
+		}
+
+		switch providerName {
+		case "GCP":
+			regions, _ := cntr["regions"].([]string)
+			for _, region := range regions {
+				if region == regionName {
+					return
+				}
+			}
+			cntr["regions"] = append(regions, regionName)
+			return
+		case "AZURE":
+			if cntr["region"] == regionName {
+				return
+			}
+		default:
+			if cntr["regionName"] == regionName {
+				return
+			}
+		}
+	}
+
+	id := newId()
+	cntr := document{
+		"id":           id,
+		"providerName": providerName,
+		"atlasCidrBlock": fmt.Sprintf(
+			"192.168.%d.0/21", (8*len(g.containers))%256),
+		"provisioned": true,
+	}
+
+	switch providerName {
+	case "GCP":
+		cntr["regions"] = []string{regionName}
+		cntr["gcpProjectId"] = "p-" + id[:20]
+		cntr["networkName"] = "nt-" + id[:19]
+	case "AZURE":
+		cntr["region"] = regionName
+		cntr["azureSubscriptionId"] = id
+		cntr["vnetName"] = "vnet_" + id
+	default:
+		cntr["regionName"] = regionName
+		cntr["vpcId"] = "vpc-" + id[:17]
+	}
+
+	g.containers[id] = cntr
+}
@@ -0,0 +1,147 @@
+package atlastest
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *Server) routeGroups(w http.ResponseWriter, r *request) {
+	segs := r.segments
+
+	if len(segs) == 1 {
+		switch r.Method {
+		case "GET":
+			s.listGroups(w, r)
+		case "POST":
+			s.createGroup(w, r)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	if len(segs) == 3 && segs[1] == "byName" {
+		if r.Method != "GET" {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		for _, grp := range s.groups {
+			if grp.doc["name"] == segs[2] {
+				writeJson(w, 200, grp.doc)
+				return
+			}
+		}
+
+		writeNotFound(w, "GROUP_NAME_NOT_FOUND", "group", segs[2])
+		return
+	}
+
+	grp := s.groups[segs[1]]
+	if grp == nil {
+		writeNotFound(w, "GROUP_NOT_FOUND", "group", segs[1])
+		return
+	}
+
+	if len(segs) == 2 {
+		switch r.Method {
+		case "GET":
+			writeJson(w, 200, grp.doc)
+		case "DELETE":
+			s.deleteGroup(w, grp)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	switch segs[2] {
+	case "clusters":
+		s.routeClusters(w, r, grp)
+	case "containers":
+		s.routeContainers(w, r, grp)
+	case "databaseUsers":
+		s.routeUsers(w, r, grp)
+	case "peers":
+		s.routePeers(w, r, grp)
+	case "whitelist":
+		s.routeWhitelist(w, r, grp)
+	default:
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+	}
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *request) {
+	ids := []string{}
+	for id := range s.groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := []interface{}{}
+	for _, id := range ids {
+		items = append(items, s.groups[id].doc)
+	}
+
+	writeList(w, r, items)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *request) {
+	name, _ := r.input["name"].(string)
+	if name == "" {
+		writeError(w, 400, "INVALID_ATTRIBUTE", "Invalid attribute name")
+		return
+	}
+
+	for _, grp := range s.groups {
+		if grp.doc["name"] == name {
+			writeError(w, 409, "GROUP_ALREADY_EXISTS",
+				"A group with name "+name+" already exists.")
+			return
+		}
+	}
+
+	id := newId()
+	grp := &group{
+		doc: document{
+			"id":           id,
+			"name":         name,
+			"orgId":        r.input["orgId"],
+			"clusterCount": 0,
+		},
+		clusters:    map[string]*object{},
+		processArgs: map[string]document{},
+		containers:  map[string]document{},
+		users:       map[string]document{},
+		peers:       map[string]*object{},
+		whitelist:   map[string]document{},
+	}
+	s.groups[id] = grp
+
+	writeJson(w, 201, grp.doc)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, grp *group) {
+	active := 0
+	for _, clst := range grp.clusters {
+		if clst.state() != "DELETED" {
+			active += 1
+		}
+	}
+
+	if active > 0 {
+		writeError(w, 409, "CANNOT_CLOSE_GROUP_ACTIVE_ATLAS_CLUSTERS",
+			"There are active clusters in this group.")
+		return
+	}
+
+	if len(grp.peers) > 0 {
+		writeError(w, 409, "CANNOT_CLOSE_GROUP_ACTIVE_PEERING_CONNECTIONS",
+			"There are active peering connections in this group.")
+		return
+	}
+
+	delete(s.groups, grp.doc["id"].(string))
+
+	writeJson(w, 202, document{})
+}
@@ -0,0 +1,90 @@
+package atlastest
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *Server) routePeers(w http.ResponseWriter, r *request, grp *group) {
+	segs := r.segments
+
+	if len(segs) == 3 {
+		switch r.Method {
+		case "GET":
+			ids := []string{}
+			for id := range grp.peers {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			items := []interface{}{}
+			for _, id := range ids {
+				items = append(items, grp.peers[id].doc)
+			}
+
+			writeList(w, r, items)
+		case "POST":
+			s.createPeer(w, r, grp)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	if len(segs) != 4 {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	id := segs[3]
+	pr := grp.peers[id]
+	if pr == nil {
+		writeNotFound(w, "PEER_NOT_FOUND", "peer", id)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		if pr.read(s.Transitions) {
+			delete(grp.peers, id)
+			writeNotFound(w, "PEER_NOT_FOUND", "peer", id)
+			return
+		}
+		writeJson(w, 200, pr.doc)
+	case "PATCH":
+		delete(r.input, "containerId")
+		merge(pr.doc, r.input)
+		writeJson(w, 200, pr.doc)
+	case "DELETE":
+		pr.transition("TERMINATING", "")
+		writeJson(w, 202, document{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createPeer(w http.ResponseWriter, r *request, grp *group) {
+	containerId, _ := r.input["containerId"].(string)
+	if grp.containers[containerId] == nil {
+		writeNotFound(w, "CLOUD_PROVIDER_CONTAINER_NOT_FOUND",
+			"container", containerId)
+		return
+	}
+
+	id := newId()
+	doc := document{
+		"id":             id,
+		"connectionId":   "pcx-" + id[:17],
+		"errorStateName": nil,
+	}
+	merge(doc, r.input)
+
+	pr := &object{
+		doc:      doc,
+		stateKey: "statusName",
+	}
+	pr.transition("INITIATING", "PENDING_ACCEPTANCE")
+	grp.peers[id] = pr
+
+	writeJson(w, 201, doc)
+}
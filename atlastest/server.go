@@ -0,0 +1,247 @@
+// Package atlastest provides an in-process fake of the MongoDB Atlas API
+// for running the provider resources without network access.
+package atlastest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const apiPath = "/api/atlas/v1.0"
+
+type document map[string]interface{}
+
+type Server struct {
+	*httptest.Server
+	Username string
+	ApiKey   string
+
+	// Algorithm is the digest algorithm offered in challenges, one of MD5,
+	// MD5-sess, SHA-256 or SHA-256-sess.
+	Algorithm string
+
+	// NonceLimit is the number of requests a nonce can authenticate before
+	// it is reported as stale, zero for no limit.
+	NonceLimit int
+
+	// Transitions is the number of reads an object spends in a
+	// transitional state such as CREATING before advancing.
+	Transitions int
+
+	lock   sync.Mutex
+	opaque string
+	nonces map[string]*nonce
+	groups map[string]*group
+}
+
+type group struct {
+	doc         document
+	clusters    map[string]*object
+	processArgs map[string]document
+	containers  map[string]document
+	users       map[string]document
+	peers       map[string]*object
+	whitelist   map[string]document
+}
+
+// object is a document that advances through a sequence of states as it is
+// read, used to simulate asynchronous provisioning.
+type object struct {
+	doc      document
+	stateKey string
+	states   []string
+	reads    int
+}
+
+type request struct {
+	*http.Request
+	segments []string
+	input    document
+}
+
+func NewServer(username, apiKey string) (srv *Server) {
+	srv = &Server{
+		Username:    username,
+		ApiKey:      apiKey,
+		Algorithm:   "MD5",
+		Transitions: 2,
+		opaque:      newId(),
+		nonces:      map[string]*nonce{},
+		groups:      map[string]*group{},
+	}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHttp))
+
+	return
+}
+
+func (s *Server) serveHttp(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.authenticate(w, r) {
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	if !strings.HasPrefix(path, apiPath+"/") {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	req := &request{
+		Request: r,
+	}
+
+	for _, seg := range strings.Split(
+		strings.TrimPrefix(path, apiPath+"/"), "/") {
+
+		seg, err := url.PathUnescape(seg)
+		if err != nil {
+			writeError(w, 400, "INVALID_PATH", err.Error())
+			return
+		}
+		req.segments = append(req.segments, seg)
+	}
+
+	if r.Body != nil && (r.Method == "POST" || r.Method == "PATCH") {
+		var input interface{}
+		err := json.NewDecoder(r.Body).Decode(&input)
+		if err != nil {
+			writeError(w, 400, "INVALID_JSON", err.Error())
+			return
+		}
+
+		switch val := input.(type) {
+		case map[string]interface{}:
+			req.input = val
+		case []interface{}:
+			req.input = document{"items": val}
+		}
+	}
+
+	if req.segments[0] != "groups" {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	s.routeGroups(w, req)
+}
+
+func (o *object) state() string {
+	state, _ := o.doc[o.stateKey].(string)
+	return state
+}
+
+// read advances the object to its next state once it has been read
+// Transitions times and returns true if the object has been removed.
+func (o *object) read(transitions int) (removed bool) {
+	if len(o.states) == 0 {
+		return
+	}
+
+	o.reads += 1
+	if o.reads < transitions {
+		return
+	}
+
+	o.reads = 0
+	next := o.states[0]
+	o.states = o.states[1:]
+
+	if next == "" {
+		removed = true
+		return
+	}
+
+	o.doc[o.stateKey] = next
+
+	return
+}
+
+func (o *object) transition(states ...string) {
+	o.doc[o.stateKey] = states[0]
+	o.states = states[1:]
+	o.reads = 0
+}
+
+func newId() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func merge(dst, src document) {
+	for key, val := range src {
+		srcDoc, srcOk := val.(map[string]interface{})
+		dstDoc, dstOk := dst[key].(map[string]interface{})
+		if srcOk && dstOk {
+			merge(dstDoc, srcDoc)
+		} else {
+			dst[key] = val
+		}
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, data interface{}) {
+	if data == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, code, detail string) {
+	writeJson(w, status, document{
+		"detail":     detail,
+		"error":      status,
+		"errorCode":  code,
+		"parameters": []string{},
+		"reason":     http.StatusText(status),
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, code, kind, id string) {
+	writeError(w, 404, code, fmt.Sprintf("No %s with ID %s exists.", kind, id))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, 405, "METHOD_NOT_ALLOWED", "Method not allowed")
+}
+
+// writeList writes a paginated list response honoring the pageNum and
+// itemsPerPage query parameters.
+func writeList(w http.ResponseWriter, r *request, items []interface{}) {
+	pageNum, _ := strconv.Atoi(r.URL.Query().Get("pageNum"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("itemsPerPage"))
+	if perPage < 1 {
+		perPage = 100
+	}
+
+	start := (pageNum - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	writeJson(w, 200, document{
+		"results":    items[start:end],
+		"totalCount": len(items),
+	})
+}
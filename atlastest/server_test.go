@@ -0,0 +1,243 @@
+package atlastest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testRequest(t *testing.T, srv *Server, method, path string,
+	input interface{}) (status int, data map[string]interface{}) {
+
+	var body *bytes.Reader
+	if input != nil {
+		inputData, err := json.Marshal(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = bytes.NewReader(inputData)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, srv.URL+apiPath+path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clnt := &http.Client{
+		Transport: &digest.Transport{
+			Username: srv.Username,
+			Password: srv.ApiKey,
+		},
+	}
+
+	resp, err := clnt.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	status = resp.StatusCode
+	data = map[string]interface{}{}
+	_ = json.NewDecoder(resp.Body).Decode(&data)
+
+	return
+}
+
+func testGroup(t *testing.T, srv *Server) string {
+	status, data := testRequest(t, srv, "POST", "/groups",
+		map[string]interface{}{
+			"name":  "test",
+			"orgId": "org",
+		})
+	if status != 201 && status != 200 {
+		t.Fatalf("create group status %d", status)
+	}
+
+	return data["id"].(string)
+}
+
+func TestServerChallenge(t *testing.T) {
+	srv := NewServer("user", "key")
+	defer srv.Close()
+	srv.Algorithm = "SHA-256"
+
+	resp, err := http.Get(srv.URL + apiPath + "/groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	header := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode != 401 ||
+		!strings.HasPrefix(header, "Digest ") ||
+		!strings.Contains(header, "algorithm=SHA-256") ||
+		!strings.Contains(header, `qop="auth"`) {
+
+		t.Errorf("status %d challenge %q, expected a SHA-256 challenge",
+			resp.StatusCode, header)
+	}
+
+	clnt := &http.Client{
+		Transport: &digest.Transport{
+			Username: srv.Username,
+			Password: "wrong",
+		},
+	}
+
+	resp, err = clnt.Get(srv.URL + apiPath + "/groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 401 {
+		t.Errorf("wrong key status %d, expected 401", resp.StatusCode)
+	}
+}
+
+func TestServerList(t *testing.T) {
+	srv := NewServer("user", "key")
+	defer srv.Close()
+	groupId := testGroup(t, srv)
+
+	entries := []interface{}{}
+	for _, cidr := range []string{
+		"10.0.0.1/32",
+		"10.0.0.2/32",
+		"10.0.0.3/32",
+		"10.0.0.4/32",
+		"10.0.0.5/32",
+	} {
+		entries = append(entries, map[string]interface{}{
+			"cidrBlock": cidr,
+		})
+	}
+
+	status, _ := testRequest(t, srv, "POST",
+		"/groups/"+groupId+"/whitelist", entries)
+	if status != 201 {
+		t.Fatalf("create whitelist status %d", status)
+	}
+
+	tests := []struct {
+		query   string
+		results int
+	}{
+		{"", 5},
+		{"?itemsPerPage=2", 2},
+		{"?itemsPerPage=2&pageNum=3", 1},
+		{"?itemsPerPage=2&pageNum=4", 0},
+	}
+
+	for _, test := range tests {
+		status, data := testRequest(t, srv, "GET",
+			"/groups/"+groupId+"/whitelist"+test.query, nil)
+		if status != 200 {
+			t.Errorf("%q status %d, expected 200", test.query, status)
+			continue
+		}
+
+		results := data["results"].([]interface{})
+		if len(results) != test.results || data["totalCount"] != 5.0 {
+			t.Errorf("%q listed %d of %v, expected %d of 5", test.query,
+				len(results), data["totalCount"], test.results)
+		}
+	}
+}
+
+func TestServerTransitions(t *testing.T) {
+	srv := NewServer("user", "key")
+	defer srv.Close()
+	groupId := testGroup(t, srv)
+
+	status, _ := testRequest(t, srv, "POST",
+		"/groups/"+groupId+"/clusters", map[string]interface{}{
+			"name":        "test",
+			"clusterType": "REPLICASET",
+			"providerSettings": map[string]interface{}{
+				"providerName":     "AWS",
+				"regionName":       "US_EAST_1",
+				"instanceSizeName": "M10",
+			},
+		})
+	if status != 201 {
+		t.Fatalf("create cluster status %d", status)
+	}
+
+	// Objects advance after Transitions reads
+	for i, expected := range []string{"CREATING", "IDLE", "IDLE"} {
+		status, data := testRequest(t, srv, "GET",
+			"/groups/"+groupId+"/clusters/test", nil)
+		if status != 200 || data["stateName"] != expected {
+			t.Errorf("read %d status %d state %v, expected %s",
+				i, status, data["stateName"], expected)
+		}
+	}
+}
+
+func TestServerContainers(t *testing.T) {
+	srv := NewServer("user", "key")
+	defer srv.Close()
+	srv.Transitions = 0
+	groupId := testGroup(t, srv)
+
+	status, _ := testRequest(t, srv, "POST",
+		"/groups/"+groupId+"/clusters", map[string]interface{}{
+			"name":        "test",
+			"clusterType": "REPLICASET",
+			"providerSettings": map[string]interface{}{
+				"providerName":     "GCP",
+				"regionName":       "CENTRAL_US",
+				"instanceSizeName": "M10",
+			},
+		})
+	if status != 201 {
+		t.Fatalf("create cluster status %d", status)
+	}
+
+	// Atlas only lists AWS containers without a provider
+	tests := []struct {
+		query   string
+		results int
+	}{
+		{"", 0},
+		{"?providerName=AWS", 0},
+		{"?providerName=GCP", 1},
+	}
+
+	for _, test := range tests {
+		_, data := testRequest(t, srv, "GET",
+			"/groups/"+groupId+"/containers"+test.query, nil)
+
+		results, _ := data["results"].([]interface{})
+		if len(results) != test.results {
+			t.Errorf("%q listed %d containers, expected %d",
+				test.query, len(results), test.results)
+		}
+	}
+}
+
+func TestServerNotFound(t *testing.T) {
+	srv := NewServer("user", "key")
+	defer srv.Close()
+	groupId := testGroup(t, srv)
+
+	for _, path := range []string{
+		"/groups/missing",
+		"/groups/" + groupId + "/clusters/missing",
+		"/groups/" + groupId + "/containers/missing",
+		"/groups/" + groupId + "/databaseUsers/admin/missing",
+		"/orgs",
+	} {
+		status, data := testRequest(t, srv, "GET", path, nil)
+		if status != 404 || data["errorCode"] == "" {
+			t.Errorf("%s status %d error %v, expected 404", path, status,
+				data["errorCode"])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package atlastest
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *Server) routeUsers(w http.ResponseWriter, r *request, grp *group) {
+	segs := r.segments
+
+	if len(segs) == 3 {
+		switch r.Method {
+		case "GET":
+			keys := []string{}
+			for key := range grp.users {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			items := []interface{}{}
+			for _, key := range keys {
+				items = append(items, grp.users[key])
+			}
+
+			writeList(w, r, items)
+		case "POST":
+			s.createUser(w, r, grp)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	if len(segs) != 5 {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	key := segs[3] + "/" + segs[4]
+	usr := grp.users[key]
+	if usr == nil {
+		writeNotFound(w, "USER_NOT_FOUND", "user", segs[4])
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, 200, usr)
+	case "PATCH":
+		delete(r.input, "password")
+		merge(usr, r.input)
+		writeJson(w, 200, usr)
+	case "DELETE":
+		delete(grp.users, key)
+		writeJson(w, 202, document{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *request, grp *group) {
+	username, _ := r.input["username"].(string)
+	password, _ := r.input["password"].(string)
+	databaseName, _ := r.input["databaseName"].(string)
+
+	if username == "" || password == "" || databaseName == "" {
+		writeError(w, 400, "INVALID_ATTRIBUTE",
+			"Username, password and databaseName are required.")
+		return
+	}
+
+	key := databaseName + "/" + username
+	if grp.users[key] != nil {
+		writeError(w, 409, "USER_ALREADY_EXISTS",
+			"The specified user already exists.")
+		return
+	}
+
+	usr := document{
+		"groupId": grp.doc["id"],
+		"roles":   []interface{}{},
+	}
+	delete(r.input, "password")
+	merge(usr, r.input)
+	grp.users[key] = usr
+
+	writeJson(w, 201, usr)
+}
@@ -0,0 +1,126 @@
+package atlastest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+func (s *Server) routeWhitelist(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	segs := r.segments
+
+	if len(segs) == 3 {
+		switch r.Method {
+		case "GET":
+			s.listWhitelist(w, r, grp)
+		case "POST":
+			s.createWhitelist(w, r, grp)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	if len(segs) != 4 {
+		writeError(w, 404, "RESOURCE_NOT_FOUND", "Unknown path")
+		return
+	}
+
+	key := whitelistKey(segs[3])
+	entry := grp.whitelist[key]
+	if entry == nil {
+		writeNotFound(w, "ATLAS_WHITELIST_NOT_FOUND",
+			"whitelist entry", segs[3])
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, 200, entry)
+	case "DELETE":
+		delete(grp.whitelist, key)
+		writeJson(w, 204, nil)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) listWhitelist(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	keys := []string{}
+	for key := range grp.whitelist {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := []interface{}{}
+	for _, key := range keys {
+		items = append(items, grp.whitelist[key])
+	}
+
+	writeList(w, r, items)
+}
+
+func (s *Server) createWhitelist(w http.ResponseWriter, r *request,
+	grp *group) {
+
+	inputs, _ := r.input["items"].([]interface{})
+	if inputs == nil {
+		writeError(w, 400, "INVALID_JSON", "Expected an array of entries.")
+		return
+	}
+
+	for _, input := range inputs {
+		inputDoc, _ := input.(map[string]interface{})
+		cidrBlock, _ := inputDoc["cidrBlock"].(string)
+		ipAddress, _ := inputDoc["ipAddress"].(string)
+
+		key := cidrBlock
+		if key == "" {
+			key = ipAddress
+		}
+		if key == "" {
+			writeError(w, 400, "INVALID_ATTRIBUTE",
+				"Either cidrBlock or ipAddress is required.")
+			return
+		}
+		key = whitelistKey(key)
+
+		entry := document{
+			"groupId":   grp.doc["id"],
+			"cidrBlock": key,
+			"comment":   inputDoc["comment"],
+		}
+		if ipAddress != "" {
+			entry["ipAddress"] = ipAddress
+		}
+		grp.whitelist[key] = entry
+	}
+
+	keys := []string{}
+	for key := range grp.whitelist {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := []interface{}{}
+	for _, key := range keys {
+		items = append(items, grp.whitelist[key])
+	}
+
+	writeJson(w, 201, document{
+		"results":    items,
+		"totalCount": len(items),
+	})
+}
+
+// whitelistKey normalizes single addresses to a /32 CIDR block.
+func whitelistKey(address string) string {
+	if !strings.Contains(address, "/") {
+		return address + "/32"
+	}
+	return address
+}
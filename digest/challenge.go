@@ -0,0 +1,222 @@
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type challenge struct {
+	lock      sync.Mutex
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	stale     bool
+	count     uint32
+}
+
+// parseChallenge parses a WWW-Authenticate header as described in
+// RFC 7616 section 3.3.
+func parseChallenge(header string) (chal *challenge, err error) {
+	header = strings.TrimSpace(header)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Digest ") {
+		err = &errortypes.AuthenticationError{
+			errors.Newf("digest: Unsupported challenge '%s'", header),
+		}
+		return
+	}
+
+	params := parseParams(header[7:])
+
+	chal = &challenge{
+		realm:     params["realm"],
+		nonce:     params["nonce"],
+		opaque:    params["opaque"],
+		algorithm: params["algorithm"],
+		stale:     strings.EqualFold(params["stale"], "true"),
+	}
+
+	if chal.nonce == "" {
+		err = &errortypes.AuthenticationError{
+			errors.New("digest: Challenge missing nonce"),
+		}
+		return
+	}
+
+	if chal.algorithm == "" {
+		chal.algorithm = "MD5"
+	}
+
+	switch strings.ToUpper(chal.algorithm) {
+	case "MD5", "MD5-SESS", "SHA-256", "SHA-256-SESS":
+	default:
+		err = &errortypes.AuthenticationError{
+			errors.Newf("digest: Unsupported algorithm '%s'",
+				chal.algorithm),
+		}
+		return
+	}
+
+	if params["qop"] != "" {
+		for _, qop := range strings.Split(params["qop"], ",") {
+			if strings.TrimSpace(qop) == "auth" {
+				chal.qop = "auth"
+				break
+			}
+		}
+
+		if chal.qop == "" {
+			err = &errortypes.AuthenticationError{
+				errors.Newf("digest: Unsupported qop '%s'", params["qop"]),
+			}
+			return
+		}
+	}
+
+	return
+}
+
+// parseParams parses a comma separated list of auth-params where values
+// may be tokens or quoted strings containing commas, equal signs and
+// backslash escapes.
+func parseParams(input string) (params map[string]string) {
+	params = map[string]string{}
+
+	for {
+		input = strings.TrimLeft(input, " \t,")
+		if input == "" {
+			return
+		}
+
+		eq := strings.IndexByte(input, '=')
+		if eq < 0 {
+			return
+		}
+
+		key := strings.ToLower(strings.TrimSpace(input[:eq]))
+		input = strings.TrimLeft(input[eq+1:], " \t")
+
+		val := ""
+		if strings.HasPrefix(input, `"`) {
+			buf := []byte{}
+			i := 1
+			for ; i < len(input); i++ {
+				if input[i] == '\\' && i+1 < len(input) {
+					i += 1
+					buf = append(buf, input[i])
+				} else if input[i] == '"' {
+					break
+				} else {
+					buf = append(buf, input[i])
+				}
+			}
+
+			val = string(buf)
+			if i < len(input) {
+				i += 1
+			}
+			input = input[i:]
+		} else {
+			end := strings.IndexByte(input, ',')
+			if end < 0 {
+				end = len(input)
+			}
+
+			val = strings.TrimSpace(input[:end])
+			input = input[end:]
+		}
+
+		params[key] = val
+	}
+}
+
+func quote(input string) string {
+	input = strings.Replace(input, `\`, `\\`, -1)
+	input = strings.Replace(input, `"`, `\"`, -1)
+	return `"` + input + `"`
+}
+
+func (c *challenge) hash(input string) string {
+	var hsh hash.Hash
+	if strings.HasPrefix(strings.ToUpper(c.algorithm), "SHA-256") {
+		hsh = sha256.New()
+	} else {
+		hsh = md5.New()
+	}
+
+	hsh.Write([]byte(input))
+
+	return fmt.Sprintf("%x", hsh.Sum(nil))
+}
+
+// authorize builds the Authorization header for a request, incrementing
+// the nonce count for each use of the nonce.
+func (c *challenge) authorize(req *http.Request,
+	username, password string) (header string, err error) {
+
+	cnonce, err := RandStr(32)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	c.count += 1
+	nc := fmt.Sprintf("%08x", c.count)
+	c.lock.Unlock()
+
+	uri := req.URL.RequestURI()
+
+	ha1 := c.hash(fmt.Sprintf("%s:%s:%s", username, c.realm, password))
+	if strings.HasSuffix(strings.ToUpper(c.algorithm), "-SESS") {
+		ha1 = c.hash(fmt.Sprintf("%s:%s:%s", ha1, c.nonce, cnonce))
+	}
+	ha2 := c.hash(fmt.Sprintf("%s:%s", req.Method, uri))
+
+	response := ""
+	if c.qop == "" {
+		response = c.hash(fmt.Sprintf("%s:%s:%s", ha1, c.nonce, ha2))
+	} else {
+		response = c.hash(fmt.Sprintf(
+			"%s:%s:%s:%s:%s:%s",
+			ha1,
+			c.nonce,
+			nc,
+			cnonce,
+			c.qop,
+			ha2,
+		))
+	}
+
+	parts := []string{
+		"username=" + quote(username),
+		"realm=" + quote(c.realm),
+		"nonce=" + quote(c.nonce),
+		"uri=" + quote(uri),
+		"algorithm=" + c.algorithm,
+		"response=" + quote(response),
+	}
+
+	if c.opaque != "" {
+		parts = append(parts, "opaque="+quote(c.opaque))
+	}
+
+	if c.qop != "" {
+		parts = append(parts,
+			"qop="+c.qop,
+			"nc="+nc,
+			"cnonce="+quote(cnonce),
+		)
+	}
+
+	header = "Digest " + strings.Join(parts, ", ")
+
+	return
+}
@@ -0,0 +1,158 @@
+package digest
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		input  string
+		params map[string]string
+	}{
+		{
+			input: `realm="MMS Public API", nonce="abc", qop="auth"`,
+			params: map[string]string{
+				"realm": "MMS Public API",
+				"nonce": "abc",
+				"qop":   "auth",
+			},
+		},
+		{
+			input: `qop="auth,auth-int", realm="a, b=c"`,
+			params: map[string]string{
+				"qop":   "auth,auth-int",
+				"realm": "a, b=c",
+			},
+		},
+		{
+			input: `nonce="a=b==", opaque="x\"y\\z"`,
+			params: map[string]string{
+				"nonce":  "a=b==",
+				"opaque": `x"y\z`,
+			},
+		},
+		{
+			input: `Algorithm=SHA-256 , stale=TRUE,,realm=test`,
+			params: map[string]string{
+				"algorithm": "SHA-256",
+				"stale":     "TRUE",
+				"realm":     "test",
+			},
+		},
+		{
+			input:  `realm="unterminated`,
+			params: map[string]string{"realm": "unterminated"},
+		},
+		{
+			input:  `token`,
+			params: map[string]string{},
+		},
+		{
+			input:  ``,
+			params: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		params := parseParams(test.input)
+		if !reflect.DeepEqual(params, test.params) {
+			t.Errorf("parseParams(%q) = %v, expected %v",
+				test.input, params, test.params)
+		}
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		header    string
+		algorithm string
+		qop       string
+		stale     bool
+		err       bool
+	}{
+		{
+			header:    `Digest realm="r", nonce="n", qop="auth"`,
+			algorithm: "MD5",
+			qop:       "auth",
+		},
+		{
+			header:    `digest nonce="n", algorithm=SHA-256-sess, stale=true`,
+			algorithm: "SHA-256-sess",
+			stale:     true,
+		},
+		{
+			header:    `Digest nonce="n", qop="auth-int, auth"`,
+			algorithm: "MD5",
+			qop:       "auth",
+		},
+		{
+			header: `Basic realm="r"`,
+			err:    true,
+		},
+		{
+			header: `Digest realm="r"`,
+			err:    true,
+		},
+		{
+			header: `Digest nonce="n", algorithm=SHA-512`,
+			err:    true,
+		},
+		{
+			header: `Digest nonce="n", qop="auth-int"`,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		chal, err := parseChallenge(test.header)
+		if test.err {
+			if err == nil {
+				t.Errorf("parseChallenge(%q) expected error", test.header)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseChallenge(%q) error %s", test.header, err)
+			continue
+		}
+
+		if chal.algorithm != test.algorithm || chal.qop != test.qop ||
+			chal.stale != test.stale {
+
+			t.Errorf("parseChallenge(%q) = %s %s %t, expected %s %s %t",
+				test.header, chal.algorithm, chal.qop, chal.stale,
+				test.algorithm, test.qop, test.stale)
+		}
+	}
+}
+
+func TestAuthorizeNonceCount(t *testing.T) {
+	chal, err := parseChallenge(
+		`Digest realm="r", nonce="n", opaque="o", qop="auth"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", "http://localhost/a?b=c", nil)
+
+	for _, nc := range []string{"00000001", "00000002"} {
+		header, err := chal.authorize(req, "user", "pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params := parseParams(strings.TrimPrefix(header, "Digest "))
+		if params["nc"] != nc {
+			t.Errorf("authorize nc = %s, expected %s", params["nc"], nc)
+		}
+		if params["uri"] != "/a?b=c" || params["opaque"] != "o" ||
+			params["username"] != "user" || len(params["cnonce"]) != 32 {
+
+			t.Errorf("authorize invalid header %s", header)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package digest
+
+import (
+	"bytes"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Number of new challenges a request is replayed with before the
+// unauthorized response is returned.
+const maxChallenges = 3
+
+// Transport is an http.RoundTripper that authenticates requests using HTTP
+// digest authentication. The server challenge is cached per host so only
+// the first request to a host is sent unauthenticated, and requests are
+// replayed with a fresh challenge when the server reports a stale or
+// expired nonce.
+type Transport struct {
+	Username string
+	Password string
+
+	// Transport is the underlying round tripper, http.DefaultTransport is
+	// used when nil.
+	Transport http.RoundTripper
+
+	lock       sync.Mutex
+	challenges map[string]*challenge
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) getChallenge(host string) *challenge {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.challenges == nil {
+		return nil
+	}
+	return t.challenges[host]
+}
+
+func (t *Transport) setChallenge(host string, chal *challenge) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.challenges == nil {
+		t.challenges = map[string]*challenge{}
+	}
+	t.challenges[host] = chal
+}
+
+// RoundTrip sends the request with the cached challenge of the host. When
+// the server rejects it with a new challenge the request is replayed with
+// that challenge up to maxChallenges times. Concurrent requests sharing a
+// nonce can reach the server out of nonce count order, a new challenge is
+// only used by the request that received it and is cached once it has
+// authenticated a request.
+func (t *Transport) RoundTrip(req *http.Request) (
+	resp *http.Response, err error) {
+
+	getBody, err := bodyFunc(req)
+	if err != nil {
+		return
+	}
+
+	host := req.URL.Host
+	chal := t.getChallenge(host)
+	shared := chal != nil
+
+	if chal == nil {
+		resp, err = t.send(req, getBody, "")
+		if err != nil {
+			return
+		}
+
+		if resp.StatusCode != 401 {
+			return
+		}
+
+		chal, err = t.rechallenge(resp)
+		if err != nil {
+			return
+		}
+		resp = nil
+	}
+
+	for i := 0; ; i++ {
+		authHeader, e := chal.authorize(req, t.Username, t.Password)
+		if e != nil {
+			err = e
+			return
+		}
+
+		resp, err = t.send(req, getBody, authHeader)
+		if err != nil {
+			return
+		}
+
+		if resp.StatusCode != 401 {
+			if !shared {
+				t.setChallenge(host, chal)
+			}
+			return
+		}
+
+		if i >= maxChallenges {
+			return
+		}
+
+		newChal, e := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+		if e != nil {
+			return
+		}
+
+		// A fresh nonce that is rejected without being stale indicates
+		// invalid credentials
+		if !newChal.stale && !shared {
+			return
+		}
+
+		drain(resp)
+		resp = nil
+		chal = newChal
+		shared = false
+	}
+}
+
+func (t *Transport) rechallenge(resp *http.Response) (
+	chal *challenge, err error) {
+
+	defer drain(resp)
+
+	chal, err = parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// send clones the request with a fresh body and the authorization header,
+// the original request is never modified as required of a RoundTripper.
+func (t *Transport) send(req *http.Request,
+	getBody func() (io.ReadCloser, error), authHeader string) (
+	resp *http.Response, err error) {
+
+	sendReq := new(http.Request)
+	*sendReq = *req
+	sendReq.Header = http.Header{}
+	for key, vals := range req.Header {
+		sendReq.Header[key] = vals
+	}
+
+	if authHeader != "" {
+		sendReq.Header.Set("Authorization", authHeader)
+	}
+
+	if getBody != nil {
+		sendReq.Body, err = getBody()
+		if err != nil {
+			err = &errortypes.RequestError{
+				errors.Wrap(err, "digest: Request body failed"),
+			}
+			return
+		}
+	}
+
+	resp, err = t.transport().RoundTrip(sendReq)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// bodyFunc returns a function producing a new copy of the request body for
+// each attempt. Requests without GetBody are buffered in memory.
+func bodyFunc(req *http.Request) (
+	getBody func() (io.ReadCloser, error), err error) {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+
+	if req.GetBody != nil {
+		req.Body.Close()
+		getBody = req.GetBody
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		err = &errortypes.ReadError{
+			errors.Wrap(err, "digest: Request body read failed"),
+		}
+		return
+	}
+
+	getBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,147 @@
+package digest
+
+import (
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlastest"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type countTransport struct {
+	count int32
+}
+
+func (c *countTransport) RoundTrip(req *http.Request) (
+	*http.Response, error) {
+
+	atomic.AddInt32(&c.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(srv *atlastest.Server, password string) (
+	*atlas.Client, *countTransport) {
+
+	counter := &countTransport{}
+	clnt := atlas.NewClient(
+		&http.Client{
+			Transport: &Transport{
+				Username:  srv.Username,
+				Password:  password,
+				Transport: counter,
+			},
+		},
+		srv.URL,
+	)
+
+	return clnt, counter
+}
+
+func TestTransportAlgorithms(t *testing.T) {
+	for _, algorithm := range []string{
+		"MD5",
+		"MD5-sess",
+		"SHA-256",
+		"SHA-256-sess",
+	} {
+		srv := atlastest.NewServer("user", "key")
+		srv.Algorithm = algorithm
+		clnt, counter := newTestClient(srv, "key")
+
+		grp, err := clnt.CreateGroup(&atlas.GroupPost{Name: "test"})
+		if err != nil {
+			t.Errorf("%s create failed %s", algorithm, err)
+			srv.Close()
+			continue
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err = clnt.GetGroup(grp.Id)
+			if err != nil {
+				t.Errorf("%s get failed %s", algorithm, err)
+			}
+		}
+
+		// Only the first request is sent without a cached challenge
+		if counter.count != 5 {
+			t.Errorf("%s sent %d requests, expected 5",
+				algorithm, counter.count)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestTransportStale(t *testing.T) {
+	srv := atlastest.NewServer("user", "key")
+	defer srv.Close()
+	srv.NonceLimit = 2
+	clnt, counter := newTestClient(srv, "key")
+
+	grp, err := clnt.CreateGroup(&atlas.GroupPost{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err = clnt.GetGroup(grp.Id)
+		if err != nil {
+			t.Fatalf("get %d failed %s", i, err)
+		}
+	}
+
+	// The challenge request and a replay for every stale nonce
+	if counter.count != 9 {
+		t.Errorf("sent %d requests, expected 9", counter.count)
+	}
+}
+
+func TestTransportUnauthorized(t *testing.T) {
+	srv := atlastest.NewServer("user", "key")
+	defer srv.Close()
+	clnt, counter := newTestClient(srv, "wrong")
+
+	_, err := clnt.CreateGroup(&atlas.GroupPost{Name: "test"})
+	if !atlas.IsStatus(err, 401) {
+		t.Fatalf("create error %v, expected 401", err)
+	}
+
+	if counter.count != 2 {
+		t.Errorf("sent %d requests, expected 2", counter.count)
+	}
+}
+
+func TestTransportConcurrent(t *testing.T) {
+	srv := atlastest.NewServer("user", "key")
+	defer srv.Close()
+	clnt, _ := newTestClient(srv, "key")
+
+	grp, err := clnt.CreateGroup(&atlas.GroupPost{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var failed int32
+	waiter := sync.WaitGroup{}
+
+	for i := 0; i < 20; i++ {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+
+			for j := 0; j < 20; j++ {
+				_, e := clnt.GetGroup(grp.Id)
+				if e != nil {
+					atomic.AddInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	waiter.Wait()
+
+	if failed != 0 {
+		t.Errorf("%d of 400 requests failed", failed)
+	}
+}
@@ -13,7 +13,8 @@ var (
 	randRe = regexp.MustCompile("[^a-zA-Z0-9]+")
 )
 
-func randStr(n int) (str string, err error) {
+// RandStr returns a crypto random alphanumeric string of length n.
+func RandStr(n int) (str string, err error) {
 	for i := 0; i < 10; i++ {
 		input, e := randBytes(int(math.Ceil(float64(n) * 1.25)))
 		if e != nil {
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
+	"github.com/pritunl/terraform-provider-mongodbatlas/transport"
+	"net"
+	"net/http"
+	"time"
+)
+
+// newClient builds the Atlas client shared by all resources through the
+// provider meta. Each request passes through the retry, digest, rate limit
+// and logging transports in that order so that retries are authenticated
+// and every round trip, including digest challenges, counts against the
+// rate limit. Timeouts are applied per round trip instead of on the http
+// client so they do not limit the total time spent retrying.
+func newClient(prvdr *schemas.Provider) *atlas.Client {
+	var trans http.RoundTripper = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	trans = &transport.Logging{
+		Transport: trans,
+	}
+
+	if prvdr.RequestsPerMinute > 0 {
+		trans = &transport.RateLimit{
+			Transport: trans,
+			Limiter: transport.NewLimiter(
+				prvdr.RequestsPerMinute,
+				prvdr.RequestsPerMinute/10,
+			),
+		}
+	}
+
+	username, password := prvdr.Credentials()
+	trans = &digest.Transport{
+		Username:  username,
+		Password:  password,
+		Transport: trans,
+	}
+
+	trans = &transport.Retry{
+		Transport:  trans,
+		MaxRetries: prvdr.MaxRetries,
+		MinWait:    1 * time.Second,
+		MaxWait:    time.Duration(prvdr.RetryMaxWait) * time.Second,
+	}
+
+	return atlas.NewClient(
+		&http.Client{
+			Transport: trans,
+		},
+		prvdr.BaseUrl,
+	)
+}
@@ -2,25 +2,70 @@ package provider
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
 	"github.com/pritunl/terraform-provider-mongodbatlas/resources"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
 )
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
-		ConfigureFunc: providerConfigure,
+	prvdr := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"username": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"public_key", "private_key"},
 			},
 			"api_key": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"public_key", "private_key"},
+			},
+			"public_key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"username", "api_key"},
+			},
+			"private_key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"username", "api_key"},
 			},
 			"org_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				DefaultFunc: schema.EnvDefaultFunc(
+					"MONGODB_ATLAS_ORG_ID",
+					nil,
+				),
+			},
+			"base_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				DefaultFunc: schema.EnvDefaultFunc(
+					"MONGODB_ATLAS_BASE_URL",
+					constants.BaseUrl,
+				),
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"requests_per_minute": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntAtLeast(0),
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
@@ -31,8 +76,25 @@ func Provider() *schema.Provider {
 			"mongodbatlas_whitelist": resources.Whitelist(),
 		},
 	}
+
+	prvdr.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(prvdr, d)
+	}
+
+	return prvdr
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	return schemas.LoadProvider(d), nil
+func providerConfigure(p *schema.Provider, d *schema.ResourceData) (
+	meta interface{}, err error) {
+
+	prvdr, err := schemas.LoadProvider(d)
+	if err != nil {
+		return
+	}
+
+	prvdr.Client = newClient(prvdr)
+	prvdr.StopContext = p.StopContext()
+	meta = prvdr
+
+	return
 }
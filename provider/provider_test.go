@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
+	"os"
+	"testing"
+)
+
+func TestProviderCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		raw      map[string]interface{}
+		username string
+		password string
+		err      bool
+	}{
+		{
+			name: "config programmatic",
+			raw: map[string]interface{}{
+				"public_key":  "public",
+				"private_key": "private",
+			},
+			username: "public",
+			password: "private",
+		},
+		{
+			name: "config programmatic env legacy",
+			env: map[string]string{
+				"MONGODB_ATLAS_USERNAME": "user",
+				"MONGODB_ATLAS_API_KEY":  "key",
+			},
+			raw: map[string]interface{}{
+				"public_key":  "public",
+				"private_key": "private",
+			},
+			username: "public",
+			password: "private",
+		},
+		{
+			name: "config legacy env programmatic",
+			env: map[string]string{
+				"MONGODB_ATLAS_PUBLIC_KEY":  "public",
+				"MONGODB_ATLAS_PRIVATE_KEY": "private",
+			},
+			raw: map[string]interface{}{
+				"username": "user",
+				"api_key":  "key",
+			},
+			username: "user",
+			password: "key",
+		},
+		{
+			name: "config public key env private key",
+			env: map[string]string{
+				"MONGODB_ATLAS_PRIVATE_KEY": "private",
+			},
+			raw: map[string]interface{}{
+				"public_key": "public",
+			},
+			username: "public",
+			password: "private",
+		},
+		{
+			name: "env both",
+			env: map[string]string{
+				"MONGODB_ATLAS_USERNAME":    "user",
+				"MONGODB_ATLAS_API_KEY":     "key",
+				"MONGODB_ATLAS_PUBLIC_KEY":  "public",
+				"MONGODB_ATLAS_PRIVATE_KEY": "private",
+			},
+			raw:      map[string]interface{}{},
+			username: "public",
+			password: "private",
+		},
+		{
+			name: "env legacy",
+			env: map[string]string{
+				"MONGODB_ATLAS_USERNAME": "user",
+				"MONGODB_ATLAS_API_KEY":  "key",
+			},
+			raw:      map[string]interface{}{},
+			username: "user",
+			password: "key",
+		},
+		{
+			name: "config both",
+			raw: map[string]interface{}{
+				"username":   "user",
+				"public_key": "public",
+			},
+			err: true,
+		},
+		{
+			name: "config incomplete",
+			raw: map[string]interface{}{
+				"public_key": "public",
+			},
+			err: true,
+		},
+		{
+			name: "none",
+			raw:  map[string]interface{}{},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		for _, key := range []string{
+			"MONGODB_ATLAS_USERNAME",
+			"MONGODB_ATLAS_API_KEY",
+			"MONGODB_ATLAS_PUBLIC_KEY",
+			"MONGODB_ATLAS_PRIVATE_KEY",
+		} {
+			os.Setenv(key, test.env[key])
+		}
+
+		test.raw["org_id"] = "org"
+		d := schema.TestResourceDataRaw(t, Provider().Schema, test.raw)
+
+		prvdr, err := schemas.LoadProvider(d)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s expected error", test.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s error %s", test.name, err)
+			continue
+		}
+
+		username, password := prvdr.Credentials()
+		if username != test.username || password != test.password {
+			t.Errorf("%s credentials %s %s, expected %s %s", test.name,
+				username, password, test.username, test.password)
+		}
+	}
+}
+
+func TestProviderValidate(t *testing.T) {
+	tests := []struct {
+		key   string
+		value int
+		valid bool
+	}{
+		{"max_retries", 0, true},
+		{"max_retries", -1, false},
+		{"retry_max_wait", 1, true},
+		{"retry_max_wait", 0, false},
+		{"requests_per_minute", 0, true},
+		{"requests_per_minute", -1, false},
+	}
+
+	for _, test := range tests {
+		_, errs := Provider().Schema[test.key].ValidateFunc(
+			test.value, test.key)
+		if (len(errs) == 0) != test.valid {
+			t.Errorf("%s %d valid %t, expected %t",
+				test.key, test.value, len(errs) == 0, test.valid)
+		}
+	}
+}
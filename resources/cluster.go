@@ -1,27 +1,53 @@
 package resources
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
-	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
 	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
-	"io/ioutil"
-	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
+var clusterSharedSizes = map[string]bool{
+	"M0": true,
+	"M2": true,
+	"M5": true,
+}
+
+// Largest disk size in GB for each dedicated instance size, larger sizes
+// support up to 4096 GB and NVMe sizes have a fixed disk.
+var clusterMaxDiskSizes = map[string]int{
+	"M10": 128,
+	"M20": 256,
+	"M30": 512,
+	"M40": 1024,
+}
+
+var clusterShardHost = regexp.MustCompile(`^(.+)-shard-(\d+)-\d+\.`)
+
 func Cluster() *schema.Resource {
 	return &schema.Resource{
 		Create: clusterCreate,
 		Read:   clusterRead,
 		Update: clusterUpdate,
 		Delete: clusterDelete,
+		Importer: &schema.ResourceImporter{
+			State: clusterImport,
+		},
+		CustomizeDiff: clusterCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(180 * time.Minute),
+			Update: schema.DefaultTimeout(180 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"group_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -34,34 +60,237 @@ func Cluster() *schema.Resource {
 				ForceNew: true,
 			},
 			"service_provider": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "AWS",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "AWS",
+				DiffSuppressFunc: clusterCaseDiffSuppress,
+				ValidateFunc:     validateStringIn("AWS", "GCP", "AZURE"),
 			},
 			"region": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "us-east-2",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "us-east-2",
+				ValidateFunc: validateRegion,
 			},
 			"size": &schema.Schema{
-				Type:     schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "M10",
+				DiffSuppressFunc: clusterSizeDiffSuppress,
+				ValidateFunc:     validateSize,
+			},
+			"disk_iops": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntBetween(100, 64000),
+			},
+			"volume_type": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: clusterCaseDiffSuppress,
+				ValidateFunc:     validateStringIn("STANDARD", "PROVISIONED"),
+			},
+			"encrypt_ebs_volume": &schema.Schema{
+				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  "M10",
+				Computed: true,
+			},
+			"disk_type_name": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: clusterCaseDiffSuppress,
+			},
+			"cluster_type": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "REPLICASET",
+				DiffSuppressFunc: clusterCaseDiffSuppress,
+				ValidateFunc:     validateStringIn("REPLICASET", "SHARDED", "GEOSHARDED"),
+			},
+			"num_shards": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Default:       1,
+				ConflictsWith: []string{"replication_spec"},
+				ValidateFunc:  validateIntBetween(1, 50),
 			},
 			"disk_size_gb": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  10,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          10,
+				DiffSuppressFunc: clusterDiskDiffSuppress,
+				ValidateFunc:     validateIntBetween(10, 4096),
 			},
 			"replication_factor": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validateIntIn(3, 5, 7),
+			},
+			"replication_spec": &schema.Schema{
+				Type:     schema.TypeList,
 				Optional: true,
-				Default:  3,
+				ConflictsWith: []string{
+					"region",
+					"replication_factor",
+					"num_shards",
+				},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zone_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "Zone 1",
+						},
+						"num_shards": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+						"regions_config": &schema.Schema{
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"region_name": &schema.Schema{
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateRegion,
+									},
+									"electable_nodes": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  3,
+									},
+									"priority": &schema.Schema{
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Default:      7,
+										ValidateFunc: validateIntBetween(0, 7),
+									},
+									"read_only_nodes": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  0,
+									},
+									"analytics_nodes": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  0,
+									},
+								},
+							},
+						},
+					},
+				},
 			},
 			"mongodb_version": &schema.Schema{
-				Type:     schema.TypeString,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "3.6",
+				ValidateFunc: validateStringIn("3.6", "4.0", "4.2", "4.4"),
+			},
+			"backup_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"provider_backup_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"auto_scaling_disk_gb_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"auto_scaling_compute_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"auto_scaling_compute_scale_down_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  "3.6",
+				Default:  false,
+			},
+			"auto_scaling_min_instance_size": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateSize,
+			},
+			"auto_scaling_max_instance_size": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateSize,
+			},
+			"paused": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"advanced_configuration": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"fail_index_key_too_long": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"javascript_enabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"minimum_enabled_tls_protocol": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"no_table_scan": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"oplog_size_mb": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+						"default_read_concern": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"default_write_concern": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"termination_protection_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"adopt_existing": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 			"container_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -75,149 +304,306 @@ func Cluster() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"mongo_uri": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mongo_uri_with_options": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"srv_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mongo_uri_private": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"srv_address_private": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"shards": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hosts": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-type clusterAutoScalingData struct {
-	DiskGbEnabled bool `json:"diskGBEnabled"`
-}
+// clusterCaseDiffSuppress ignores changes in case.
+func clusterCaseDiffSuppress(k, old, new string,
+	d *schema.ResourceData) bool {
 
-type clusterProviderData struct {
-	ProviderName     string `json:"providerName"`
-	RegionName       string `json:"regionName"`
-	InstanceSizeName string `json:"instanceSizeName"`
+	return strings.EqualFold(old, new)
 }
 
-type clusterPostData struct {
-	AutoScaling         clusterAutoScalingData `json:"autoScaling"`
-	Name                string                 `json:"name"`
-	MongoDbMajorVersion string                 `json:"mongoDBMajorVersion"`
-	ReplicationFactor   int                    `json:"replicationFactor"`
-	BackupEnabled       bool                   `json:"backupEnabled"`
-	DiskSizeGb          int                    `json:"diskSizeGB"`
-	ProviderSettings    clusterProviderData    `json:"providerSettings"`
-}
+// clusterVersionLess returns true when the MongoDB major version x is
+// older than y.
+func clusterVersionLess(x, y string) bool {
+	xParts := strings.SplitN(x, ".", 2)
+	yParts := strings.SplitN(y, ".", 2)
+
+	for i := 0; i < 2; i++ {
+		xNum := 0
+		yNum := 0
+		if i < len(xParts) {
+			xNum, _ = strconv.Atoi(xParts[i])
+		}
+		if i < len(yParts) {
+			yNum, _ = strconv.Atoi(yParts[i])
+		}
 
-type clusterPutData struct {
-	AutoScaling         clusterAutoScalingData `json:"autoScaling"`
-	MongoDbMajorVersion string                 `json:"mongoDBMajorVersion"`
-	ReplicationFactor   int                    `json:"replicationFactor"`
-	BackupEnabled       bool                   `json:"backupEnabled"`
-	DiskSizeGb          int                    `json:"diskSizeGB"`
-	ProviderSettings    clusterProviderData    `json:"providerSettings"`
-}
+		if xNum != yNum {
+			return xNum < yNum
+		}
+	}
 
-type clusterData struct {
-	Id                  string              `json:"id"`
-	Name                string              `json:"name"`
-	GroupId             string              `json:"groupId"`
-	StateName           string              `json:"stateName"`
-	MongoUri            string              `json:"mongoURI"`
-	MongoUriWithOptions string              `json:"mongoURIWithOptions"`
-	MongoDbMajorVersion string              `json:"mongoDBMajorVersion"`
-	Paused              bool                `json:"paused"`
-	ProviderSettings    clusterProviderData `json:"providerSettings"`
+	return false
 }
 
-type containerData struct {
-	Id             string `json:"id"`
-	ProviderName   string `json:"providerName"`
-	RegionName     string `json:"regionName"`
-	VpcId          string `json:"vpcId"`
-	AtlasCidrBlock string `json:"atlasCidrBlock"`
-	Provisioned    bool   `json:"provisioned"`
-}
+// clusterSizeDiffSuppress ignores size changes made by Atlas when compute
+// autoscaling is enabled.
+func clusterSizeDiffSuppress(k, old, new string,
+	d *schema.ResourceData) bool {
+
+	if strings.EqualFold(old, new) {
+		return true
+	}
 
-type containerResp struct {
-	Results []*containerData `json:"results"`
+	return old != "" && d.Get("auto_scaling_compute_enabled").(bool)
 }
 
-func (c *clusterData) Available() bool {
-	switch c.StateName {
-	case "IDLE", "REPAIRING":
-		return true
-	default:
+// clusterDiskDiffSuppress ignores disk growth made by Atlas when disk
+// autoscaling is enabled.
+func clusterDiskDiffSuppress(k, old, new string,
+	d *schema.ResourceData) bool {
+
+	if !d.Get("auto_scaling_disk_gb_enabled").(bool) {
 		return false
 	}
+
+	oldSize, _ := strconv.Atoi(old)
+	newSize, _ := strconv.Atoi(new)
+
+	return oldSize > newSize
 }
 
-func (c *clusterData) Updating() bool {
-	switch c.StateName {
-	case "UPDATING", "REPAIRING":
-		return true
-	default:
-		return false
+// clusterAutoScaling returns the cluster autoscaling settings.
+func clusterAutoScaling(clst *schemas.Cluster) atlas.ClusterAutoScaling {
+	return atlas.ClusterAutoScaling{
+		DiskGbEnabled: clst.AutoScalingDiskGb,
+		Compute: atlas.ClusterComputeAutoScaling{
+			Enabled:          clst.AutoScalingCompute,
+			ScaleDownEnabled: clst.AutoScalingScaleDown,
+		},
 	}
 }
 
-func containerGet(prvdr *schemas.Provider, clst *schemas.Cluster) (
-	container *containerData, err error) {
+// clusterRegionName converts a region of the cluster provider to the
+// Atlas format such as us-east-2 to US_EAST_2.
+func clusterRegionName(clst *schemas.Cluster, region string) string {
+	name, _ := regionAtlas(clst.ServiceProvider, region)
+	return name
+}
 
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/containers",
-			clst.GroupId,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Containers request failed"),
+// clusterShared returns true when the cluster size is a shared tier.
+func clusterShared(clst *schemas.Cluster) bool {
+	return clusterSharedSizes[strings.ToUpper(clst.Size)]
+}
+
+// clusterValidate checks the provider, regions, size and the provider
+// specific arguments of the cluster.
+func clusterValidate(clst *schemas.Cluster) (err error) {
+	provider := strings.ToUpper(clst.ServiceProvider)
+
+	if providerRegions[provider] == nil {
+		err = &errortypes.ParseError{
+			errors.Newf("resources: Unknown service_provider %s, "+
+				"expected AWS, GCP or AZURE", clst.ServiceProvider),
 		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
+	if clusterShared(clst) && (len(clst.ReplicationSpecs) != 0 ||
+		strings.ToUpper(clst.ClusterType) != "REPLICASET" ||
+		clst.AutoScalingCompute || clst.ProviderBackup ||
+		clst.AdvancedConfig != nil) {
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Containers request failed"),
+		err = &errortypes.ParseError{
+			errors.Newf("resources: Shared tier size %s does not "+
+				"support replication_spec, sharding, compute autoscaling, "+
+				"provider_backup_enabled or advanced_configuration",
+				clst.Size),
 		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	if clst.Paused && clusterShared(clst) {
+		err = &errortypes.ParseError{
+			errors.Newf("resources: Shared tier size %s cannot be paused",
+				clst.Size),
 		}
+		return
+	}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Containers request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+	regions := []string{}
+	if len(clst.ReplicationSpecs) == 0 {
+		regions = append(regions, clst.Region)
+	}
+	for _, spec := range clst.ReplicationSpecs {
+		for _, rgn := range spec.Regions {
+			regions = append(regions, rgn.RegionName)
 		}
-		return
 	}
 
-	data := &containerResp{}
-	err = json.NewDecoder(resp.Body).Decode(data)
-	if err != nil {
+	for _, region := range regions {
+		if _, ok := regionAtlas(provider, region); !ok {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Region %s is not available "+
+					"on %s", region, provider),
+			}
+			return
+		}
+	}
+
+	sizes := []string{clst.Size}
+	if clst.AutoScalingCompute {
+		sizes = append(sizes, clst.AutoScalingMinSize,
+			clst.AutoScalingMaxSize)
+	}
+
+	for _, size := range sizes {
+		size = strings.ToUpper(size)
+		if size != "" && !providerSizes[provider][size] &&
+			!clusterSharedSizes[size] {
+
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Size %s is not available on %s",
+					size, provider),
+			}
+			return
+		}
+	}
+
+	size := strings.ToUpper(clst.Size)
+	if !clusterShared(clst) && !strings.HasSuffix(size, "_NVME") {
+		maxDisk := clusterMaxDiskSizes[size]
+		if maxDisk == 0 {
+			maxDisk = 4096
+		}
+
+		if clst.DiskSizeGb < 10 || clst.DiskSizeGb > maxDisk {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: disk_size_gb %d is not available "+
+					"on %s, expected 10 to %d", clst.DiskSizeGb, size,
+					maxDisk),
+			}
+			return
+		}
+	}
+
+	for _, spec := range clst.ReplicationSpecs {
+		electable := 0
+		priority := 0
+		for _, rgn := range spec.Regions {
+			electable += rgn.ElectableNodes
+			if rgn.Priority > priority {
+				priority = rgn.Priority
+			}
+		}
+
+		if electable != 3 && electable != 5 && electable != 7 {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Zone %s has %d electable nodes, "+
+					"expected 3, 5 or 7", spec.ZoneName, electable),
+			}
+			return
+		}
+
+		if priority != 7 {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Zone %s must have a region with "+
+					"priority 7", spec.ZoneName),
+			}
+			return
+		}
+	}
+
+	if provider != "AWS" && (clst.DiskIops != 0 ||
+		clst.VolumeType != "" || (clst.EncryptEbsVolume != nil &&
+		*clst.EncryptEbsVolume)) {
+
 		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Containers decode failed"),
+			errors.New("resources: disk_iops, volume_type and " +
+				"encrypt_ebs_volume are only available on AWS"),
 		}
 		return
 	}
 
-	if data.Results == nil {
+	if provider != "AZURE" && clst.DiskTypeName != "" {
 		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Containers response empty"),
+			errors.New("resources: disk_type_name is only available " +
+				"on AZURE"),
 		}
 		return
 	}
 
-	region := strings.Replace(strings.ToUpper(clst.Region), "-", "_", -1)
+	return
+}
+
+// clusterPrimaryRegion returns the region with the highest priority in the
+// first replication spec or the region argument when no spec is set.
+func clusterPrimaryRegion(clst *schemas.Cluster) string {
+	region := clst.Region
+	priority := -1
+
+	if len(clst.ReplicationSpecs) > 0 {
+		for _, rgn := range clst.ReplicationSpecs[0].Regions {
+			if rgn.Priority > priority {
+				region = rgn.RegionName
+				priority = rgn.Priority
+			}
+		}
+	}
+
+	return region
+}
+
+func containerGet(clnt *atlas.Client, clst *schemas.Cluster) (
+	container *atlas.Container, err error) {
+
+	provider := strings.ToUpper(clst.ServiceProvider)
+
+	cntrs, err := clnt.ListContainers(clst.GroupId, provider)
+	if err != nil {
+		return
+	}
 
-	for _, cntr := range data.Results {
-		if cntr.Provisioned && region == cntr.RegionName {
+	region := clusterRegionName(clst, clusterPrimaryRegion(clst))
+
+	for _, cntr := range cntrs {
+		if cntr.Provisioned && cntr.Matches(provider, region) {
 			container = cntr
 			return
 		}
@@ -226,279 +612,896 @@ func containerGet(prvdr *schemas.Provider, clst *schemas.Cluster) (
 	return
 }
 
-func clusterGet(prvdr *schemas.Provider, groupId, name string) (
-	data *clusterData, err error) {
+func clusterProvider(clst *schemas.Cluster) atlas.ClusterProvider {
+	if clusterShared(clst) {
+		return atlas.ClusterProvider{
+			ProviderName:        "TENANT",
+			BackingProviderName: strings.ToUpper(clst.ServiceProvider),
+			RegionName:          clusterRegionName(clst, clst.Region),
+			InstanceSizeName:    strings.ToUpper(clst.Size),
+		}
+	}
 
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/clusters/%s",
-			groupId,
-			name,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
+	prvdr := atlas.ClusterProvider{
+		ProviderName:     strings.ToUpper(clst.ServiceProvider),
+		InstanceSizeName: strings.ToUpper(clst.Size),
+	}
+
+	if len(clst.ReplicationSpecs) == 0 {
+		prvdr.RegionName = clusterRegionName(clst, clst.Region)
+	}
+
+	switch prvdr.ProviderName {
+	case "AWS":
+		prvdr.DiskIops = clst.DiskIops
+		prvdr.VolumeType = strings.ToUpper(clst.VolumeType)
+		// Atlas encrypts volumes unless encryption is disabled
+		prvdr.EncryptEbsVolume = clst.EncryptEbsVolume
+	case "AZURE":
+		prvdr.DiskTypeName = strings.ToUpper(clst.DiskTypeName)
+	}
+
+	if clst.AutoScalingCompute {
+		prvdr.AutoScaling = &atlas.ClusterProviderAutoScaling{
+			Compute: atlas.ClusterProviderComputeAutoScaling{
+				MinInstanceSize: strings.ToUpper(clst.AutoScalingMinSize),
+				MaxInstanceSize: strings.ToUpper(clst.AutoScalingMaxSize),
+			},
 		}
-		return
 	}
 
-	req.Header.Set("Accept", "application/json")
+	return prvdr
+}
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
+func clusterReplicationSpecs(clst *schemas.Cluster) (
+	specs []*atlas.ClusterReplicationSpec) {
+
+	for _, spec := range clst.ReplicationSpecs {
+		regions := map[string]atlas.ClusterRegionConfig{}
+
+		for _, rgn := range spec.Regions {
+			regions[clusterRegionName(clst, rgn.RegionName)] =
+				atlas.ClusterRegionConfig{
+					ElectableNodes: rgn.ElectableNodes,
+					Priority:       rgn.Priority,
+					ReadOnlyNodes:  rgn.ReadOnlyNodes,
+					AnalyticsNodes: rgn.AnalyticsNodes,
+				}
 		}
-		return
+
+		specs = append(specs, &atlas.ClusterReplicationSpec{
+			Id:            spec.Id,
+			NumShards:     spec.NumShards,
+			ZoneName:      spec.ZoneName,
+			RegionsConfig: regions,
+		})
+	}
+
+	return
+}
+
+func clusterPost(clnt *atlas.Client, clst *schemas.Cluster) (err error) {
+	postData := &atlas.ClusterPost{
+		AutoScaling:           clusterAutoScaling(clst),
+		Name:                  clst.Name,
+		ClusterType:           strings.ToUpper(clst.ClusterType),
+		MongoDbMajorVersion:   clst.MongoDbVersion,
+		ReplicationSpecs:      clusterReplicationSpecs(clst),
+		BackupEnabled:         clst.BackupEnabled && !clst.ProviderBackup,
+		ProviderBackupEnabled: clst.ProviderBackup,
+		DiskSizeGb:            clst.DiskSizeGb,
+		ProviderSettings:      clusterProvider(clst),
+		TerminationProtection: clst.TerminationProtection,
+	}
+
+	if postData.ReplicationSpecs == nil {
+		postData.NumShards = clst.NumShards
+		postData.ReplicationFactor = clst.ReplicationFactor
+	}
+
+	// Shared tiers have a fixed disk size and their own backups
+	if clusterShared(clst) {
+		postData.AutoScaling = atlas.ClusterAutoScaling{}
+		postData.BackupEnabled = false
+		postData.DiskSizeGb = 0
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
+	_, err = clnt.CreateCluster(clst.GroupId, postData)
+	if err != nil {
 		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	}
+
+	return
+}
+
+// clusterPut sends the changed arguments of the cluster. Provider
+// settings are sent together as Atlas requires the provider and size with
+// any change to them. Upgrading from a shared tier sends every argument as
+// the shared tier ignores the dedicated arguments.
+func clusterPut(d *schema.ResourceData, clnt *atlas.Client,
+	clst *schemas.Cluster) (data *atlas.Cluster, err error) {
+
+	shared := clusterShared(clst)
+	putData := &atlas.ClusterPut{}
+
+	oldSize, _ := d.GetChange("size")
+	upgrade := !shared &&
+		clusterSharedSizes[strings.ToUpper(oldSize.(string))]
+
+	hasChange := func(key string) bool {
+		return upgrade || d.HasChange(key)
+	}
+
+	if hasChange("auto_scaling_disk_gb_enabled") ||
+		hasChange("auto_scaling_compute_enabled") ||
+		hasChange("auto_scaling_compute_scale_down_enabled") {
+
+		autoScaling := atlas.ClusterAutoScaling{}
+		if !shared {
+			autoScaling = clusterAutoScaling(clst)
 		}
+		putData.AutoScaling = &autoScaling
+	}
+
+	if hasChange("mongodb_version") {
+		putData.MongoDbMajorVersion = clst.MongoDbVersion
+	}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Clusters request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+	if hasChange("cluster_type") {
+		putData.ClusterType = strings.ToUpper(clst.ClusterType)
+	}
+
+	if len(clst.ReplicationSpecs) == 0 {
+		if hasChange("num_shards") {
+			putData.NumShards = clst.NumShards
 		}
-		return
+		if hasChange("replication_factor") {
+			putData.ReplicationFactor = clst.ReplicationFactor
+		}
+	} else if hasChange("replication_spec") {
+		putData.ReplicationSpecs = clusterReplicationSpecs(clst)
+	}
+
+	if hasChange("backup_enabled") ||
+		hasChange("provider_backup_enabled") {
+
+		backup := clst.BackupEnabled && !clst.ProviderBackup && !shared
+		providerBackup := clst.ProviderBackup
+		putData.BackupEnabled = &backup
+		putData.ProviderBackupEnabled = &providerBackup
+	}
+
+	if hasChange("disk_size_gb") && !shared {
+		putData.DiskSizeGb = clst.DiskSizeGb
+	}
+
+	for _, key := range []string{
+		"service_provider",
+		"region",
+		"size",
+		"disk_iops",
+		"volume_type",
+		"encrypt_ebs_volume",
+		"disk_type_name",
+		"replication_spec",
+		"auto_scaling_compute_enabled",
+		"auto_scaling_min_instance_size",
+		"auto_scaling_max_instance_size",
+	} {
+		if hasChange(key) {
+			prvdr := clusterProvider(clst)
+			putData.ProviderSettings = &prvdr
+			break
+		}
+	}
+
+	if hasChange("termination_protection_enabled") {
+		protection := clst.TerminationProtection
+		putData.TerminationProtection = &protection
 	}
 
-	data = &clusterData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
+	data, err = clnt.UpdateCluster(clst.GroupId, clst.Name, putData)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Clusters decode failed"),
+		return
+	}
+
+	return
+}
+
+func clusterWait(ctx context.Context, clnt *atlas.Client,
+	groupId, name string) (data *atlas.Cluster, err error) {
+
+	err = waitFor(ctx, "cluster "+name, func() (
+		state string, done bool, err error) {
+
+		data, err = clnt.GetCluster(groupId, name)
+		if err != nil {
+			return
+		}
+
+		if data == nil {
+			err = &errortypes.NotFoundError{
+				errors.New("resources: Cluster not found"),
+			}
+			return
+		}
+
+		state = data.StateName
+
+		if data.Deleting() {
+			err = &errortypes.RequestError{
+				errors.Newf("resources: Cluster %s is being deleted", name),
+			}
+			return
 		}
+
+		done = data.Available()
+
+		return
+	})
+	if err != nil {
 		return
 	}
 
 	return
 }
 
-func clusterPost(prvdr *schemas.Provider, clst *schemas.Cluster) (err error) {
-	region := strings.Replace(strings.ToUpper(clst.Region), "-", "_", -1)
+func clusterSetContainer(d *schema.ResourceData, clnt *atlas.Client,
+	clst *schemas.Cluster) (err error) {
 
-	data := clusterPostData{
-		AutoScaling: clusterAutoScalingData{
-			DiskGbEnabled: true,
-		},
-		Name:                clst.Name,
-		MongoDbMajorVersion: clst.MongoDbVersion,
-		ReplicationFactor:   clst.ReplicationFactor,
-		BackupEnabled:       true,
-		DiskSizeGb:          clst.DiskSizeGb,
-		ProviderSettings: clusterProviderData{
-			ProviderName:     strings.ToUpper(clst.ServiceProvider),
-			RegionName:       region,
-			InstanceSizeName: strings.ToUpper(clst.Size),
-		},
+	// Shared tier clusters do not have a network container
+	if clusterShared(clst) {
+		d.Set("container_id", "")
+		d.Set("atlas_vpc_id", "")
+		d.Set("atlas_cidr", "")
+		return
 	}
 
-	body, err := json.Marshal(data)
+	cntr, err := containerGet(clnt, clst)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Cluster marshal failed"),
-		}
 		return
 	}
 
-	req, err := http.NewRequest(
-		"POST",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/clusters",
-			clst.GroupId,
-		),
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
+	if cntr == nil {
+		err = &errortypes.NotFoundError{
+			errors.New("resources: Container not found"),
 		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	d.Set("container_id", cntr.Id)
+	d.Set("atlas_vpc_id", cntr.NetworkId())
+	d.Set("atlas_cidr", cntr.AtlasCidrBlock)
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+	return
+}
+
+// clusterPause pauses or resumes a cluster and waits for the change to
+// complete. Shared tiers are rejected by clusterValidate.
+func clusterPause(ctx context.Context, clnt *atlas.Client,
+	clst *schemas.Cluster, paused bool) (data *atlas.Cluster, err error) {
+
+	data, err = clnt.PauseCluster(clst.GroupId, clst.Name, paused)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	if data == nil {
+		err = &errortypes.NotFoundError{
+			errors.New("resources: Cluster not found"),
 		}
+		return
+	}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Clusters request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
+	data, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
+	if err != nil {
 		return
 	}
 
 	return
 }
 
-func clusterPut(prvdr *schemas.Provider, clst *schemas.Cluster) (
-	data *clusterData, err error) {
+// clusterHasChange returns true when any argument of the cluster document
+// has changed. Atlas rejects updates combined with a pause or resume and
+// the advanced configuration is updated separately.
+func clusterHasChange(d *schema.ResourceData) bool {
+	for key := range Cluster().Schema {
+		switch key {
+		case "paused", "advanced_configuration", "adopt_existing":
+			continue
+		}
+		if d.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterPausedChange returns the first argument changed by the plan
+// ignoring the changes hidden by the diff suppress funcs, which are not
+// applied to a ResourceDiff.
+func clusterPausedChange(d *schema.ResourceDiff) string {
+	keys := []string{}
+	for key, sch := range Cluster().Schema {
+		switch key {
+		case "paused", "adopt_existing":
+			continue
+		}
+		if sch.Optional || sch.Required {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !d.HasChange(key) {
+			continue
+		}
+
+		oldVal, newVal := d.GetChange(key)
 
-	region := strings.Replace(strings.ToUpper(clst.Region), "-", "_", -1)
+		switch key {
+		case "service_provider", "volume_type", "disk_type_name",
+			"cluster_type":
 
-	putData := clusterPutData{
-		AutoScaling: clusterAutoScalingData{
-			DiskGbEnabled: true,
-		},
-		MongoDbMajorVersion: clst.MongoDbVersion,
-		ReplicationFactor:   clst.ReplicationFactor,
-		BackupEnabled:       true,
-		DiskSizeGb:          clst.DiskSizeGb,
-		ProviderSettings: clusterProviderData{
-			ProviderName:     strings.ToUpper(clst.ServiceProvider),
-			RegionName:       region,
-			InstanceSizeName: strings.ToUpper(clst.Size),
+			if strings.EqualFold(oldVal.(string), newVal.(string)) {
+				continue
+			}
+		case "size":
+			if strings.EqualFold(oldVal.(string), newVal.(string)) ||
+				(oldVal.(string) != "" &&
+					d.Get("auto_scaling_compute_enabled").(bool)) {
+
+				continue
+			}
+		case "disk_size_gb":
+			if oldVal.(int) > newVal.(int) &&
+				d.Get("auto_scaling_disk_gb_enabled").(bool) {
+
+				continue
+			}
+		}
+
+		return key
+	}
+
+	return ""
+}
+
+// clusterSetProcessArgs updates the advanced configuration of the cluster
+// and waits for the rolling restart to complete.
+func clusterSetProcessArgs(ctx context.Context, clnt *atlas.Client,
+	clst *schemas.Cluster) (data *atlas.ProcessArgs, err error) {
+
+	conf := clst.AdvancedConfig
+
+	data, err = clnt.UpdateProcessArgs(clst.GroupId, clst.Name,
+		&atlas.ProcessArgs{
+			FailIndexKeyTooLong:       conf.FailIndexKeyTooLong,
+			JavascriptEnabled:         conf.JavascriptEnabled,
+			MinimumEnabledTlsProtocol: conf.MinimumEnabledTlsProtocol,
+			NoTableScan:               conf.NoTableScan,
+			OplogSizeMb:               conf.OplogSizeMb,
+			DefaultReadConcern:        conf.DefaultReadConcern,
+			DefaultWriteConcern:       conf.DefaultWriteConcern,
 		},
+	)
+	if err != nil {
+		return
 	}
 
-	body, err := json.Marshal(putData)
+	_, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Cluster marshal failed"),
-		}
 		return
 	}
 
-	req, err := http.NewRequest(
-		"PATCH",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/clusters/%s",
-			clst.GroupId,
-			clst.Name,
-		),
-		bytes.NewBuffer(body),
-	)
+	return
+}
+
+// clusterSetAdvancedConfig sets the advanced configuration from the
+// cluster process arguments.
+func clusterSetAdvancedConfig(d *schema.ResourceData,
+	data *atlas.ProcessArgs) {
+
+	d.Set("advanced_configuration", []interface{}{
+		map[string]interface{}{
+			"fail_index_key_too_long":      data.FailIndexKeyTooLong,
+			"javascript_enabled":           data.JavascriptEnabled,
+			"minimum_enabled_tls_protocol": data.MinimumEnabledTlsProtocol,
+			"no_table_scan":                data.NoTableScan,
+			"oplog_size_mb":                data.OplogSizeMb,
+			"default_read_concern":         data.DefaultReadConcern,
+			"default_write_concern":        data.DefaultWriteConcern,
+		},
+	})
+}
+
+// clusterDel deletes a cluster and waits until it is removed.
+func clusterDel(ctx context.Context, clnt *atlas.Client,
+	groupId, name string) (err error) {
+
+	err = clnt.DeleteCluster(groupId, name)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
-		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	err = waitFor(ctx, "cluster "+name+" deletion", func() (
+		state string, done bool, err error) {
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+		clstData, err := clnt.GetCluster(groupId, name)
+		if err != nil {
+			return
+		}
+
+		if clstData == nil {
+			state = "DELETED"
+			done = true
+			return
+		}
+
+		state = clstData.StateName
+		done = clstData.Deleted()
+
+		return
+	})
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Clusters request failed"),
+		return
+	}
+
+	return
+}
+
+// clusterCustomizeDiff validates the planned cluster so invalid
+// combinations of arguments fail before any change is applied. Values that
+// are not known until apply are checked again by create and update. A
+// provider change replaces the cluster while version downgrades, disk
+// shrinks, changes to a shared tier and changes to a paused cluster are
+// rejected.
+func clusterCustomizeDiff(d *schema.ResourceDiff, m interface{}) (
+	err error) {
+
+	if d.Id() != "" {
+		// Atlas cannot move a cluster to another provider, the
+		// replacement is planned and validated again without the state
+		// that holds the provider specific values of the current cluster
+		if d.HasChange("service_provider") {
+			oldPrvdr, newPrvdr := d.GetChange("service_provider")
+			if !strings.EqualFold(oldPrvdr.(string), newPrvdr.(string)) {
+				err = d.ForceNew("service_provider")
+				return
+			}
+		}
+
+		// Atlas rejects changes to a paused cluster
+		oldPaused, newPaused := d.GetChange("paused")
+		if oldPaused.(bool) && newPaused.(bool) {
+			key := clusterPausedChange(d)
+			if key != "" {
+				err = &errortypes.ParseError{
+					errors.Newf("resources: Cluster %s cannot be changed "+
+						"while paused, set paused to false to apply the "+
+						"change", key),
+				}
+				return
+			}
 		}
+
+		if d.HasChange("mongodb_version") {
+			oldVer, newVer := d.GetChange("mongodb_version")
+			if clusterVersionLess(newVer.(string), oldVer.(string)) {
+				err = &errortypes.ParseError{
+					errors.Newf("resources: Cluster mongodb_version "+
+						"cannot be downgraded from %s to %s",
+						oldVer, newVer),
+				}
+				return
+			}
+		}
+
+		// Shared tiers can be upgraded to dedicated tiers but not
+		// downgraded
+		if d.HasChange("size") {
+			oldSize, newSize := d.GetChange("size")
+			if !clusterSharedSizes[strings.ToUpper(oldSize.(string))] &&
+				clusterSharedSizes[strings.ToUpper(newSize.(string))] {
+
+				err = &errortypes.ParseError{
+					errors.Newf("resources: Cluster cannot be changed "+
+						"from size %s to the shared tier %s",
+						oldSize, newSize),
+				}
+				return
+			}
+		}
+
+		// Disk growth made by Atlas is suppressed when disk autoscaling
+		// is enabled
+		if d.HasChange("disk_size_gb") &&
+			!d.Get("auto_scaling_disk_gb_enabled").(bool) {
+
+			oldSize, newSize := d.GetChange("disk_size_gb")
+			if newSize.(int) < oldSize.(int) {
+				err = &errortypes.ParseError{
+					errors.Newf("resources: Cluster disk_size_gb "+
+						"cannot be reduced from %d to %d",
+						oldSize, newSize),
+				}
+				return
+			}
+		}
+	}
+
+	for _, key := range []string{
+		"service_provider",
+		"region",
+		"size",
+		"disk_size_gb",
+		"replication_spec",
+		"auto_scaling_min_instance_size",
+		"auto_scaling_max_instance_size",
+	} {
+		if !d.NewValueKnown(key) {
+			return
+		}
+	}
+
+	err = clusterValidate(schemas.LoadCluster(d))
+	if err != nil {
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
+	return
+}
+
+// clusterNormalize returns the current value when it only differs from
+// the Atlas value by case or separators to prevent a spurious diff.
+func clusterNormalize(cur, val string) string {
+	if cur != "" && strings.EqualFold(
+		strings.Replace(cur, "-", "_", -1),
+		strings.Replace(val, "-", "_", -1),
+	) {
+		return cur
+	}
+	return val
+}
+
+// clusterMultiRegion returns true when the cluster is not described by the
+// single region and replication factor arguments.
+func clusterMultiRegion(data *atlas.Cluster) bool {
+	if len(data.ReplicationSpecs) > 1 {
+		return true
+	}
+	for _, spec := range data.ReplicationSpecs {
+		if len(spec.RegionsConfig) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterFlattenSpecs converts the Atlas replication specs to the
+// replication_spec format. Regions are kept in the order of the current
+// configuration with new regions sorted by priority.
+func clusterFlattenSpecs(d *schema.ResourceData, data *atlas.Cluster) (
+	specs []interface{}) {
+
+	clst := schemas.LoadCluster(d)
+	specs = []interface{}{}
+
+	for i, spec := range data.ReplicationSpecs {
+		order := []string{}
+		if i < len(clst.ReplicationSpecs) {
+			for _, rgn := range clst.ReplicationSpecs[i].Regions {
+				order = append(order, rgn.RegionName)
+			}
+		}
+
+		names := []string{}
+		for name := range spec.RegionsConfig {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(x, y int) bool {
+			px := spec.RegionsConfig[names[x]].Priority
+			py := spec.RegionsConfig[names[y]].Priority
+			if px != py {
+				return px > py
+			}
+			return names[x] < names[y]
+		})
+
+		regions := []interface{}{}
+		added := map[string]bool{}
+
+		for _, cur := range order {
+			name := clusterRegionName(clst, cur)
+			if rgn, ok := spec.RegionsConfig[name]; ok && !added[name] {
+				regions = append(regions, clusterFlattenRegion(cur, rgn))
+				added[name] = true
+			}
+		}
+
+		for _, name := range names {
+			if !added[name] {
+				regions = append(regions, clusterFlattenRegion(
+					regionProvider(clst.ServiceProvider, name),
+					spec.RegionsConfig[name],
+				))
+			}
+		}
+
+		specs = append(specs, map[string]interface{}{
+			"id":             spec.Id,
+			"zone_name":      spec.ZoneName,
+			"num_shards":     spec.NumShards,
+			"regions_config": regions,
+		})
+	}
+
+	return
+}
+
+func clusterFlattenRegion(name string,
+	rgn atlas.ClusterRegionConfig) map[string]interface{} {
+
+	return map[string]interface{}{
+		"region_name":     name,
+		"electable_nodes": rgn.ElectableNodes,
+		"priority":        rgn.Priority,
+		"read_only_nodes": rgn.ReadOnlyNodes,
+		"analytics_nodes": rgn.AnalyticsNodes,
+	}
+}
+
+// clusterSetData sets the cluster arguments from the Atlas cluster
+// document in the same format as the configuration. Replication specs are
+// only set when configured or when the cluster spans several regions, the
+// region, replication factor and shards are left unset when they are
+// configured.
+func clusterSetData(d *schema.ResourceData, data *atlas.Cluster) {
+	prvdr := data.ProviderSettings
+	provider := data.Provider()
+	specs := len(d.Get("replication_spec").([]interface{})) > 0
+	multiRegion := clusterMultiRegion(data)
+
+	d.Set("service_provider", clusterNormalize(
+		d.Get("service_provider").(string), provider))
+	if !specs && !multiRegion && prvdr.RegionName != "" {
+		d.Set("region", regionNormalize(provider,
+			d.Get("region").(string), prvdr.RegionName))
+	}
+	d.Set("size", clusterNormalize(
+		d.Get("size").(string), prvdr.InstanceSizeName))
+	if provider == "AWS" {
+		d.Set("disk_iops", prvdr.DiskIops)
+		d.Set("volume_type", prvdr.VolumeType)
+		if prvdr.EncryptEbsVolume != nil {
+			d.Set("encrypt_ebs_volume", *prvdr.EncryptEbsVolume)
+		}
+	}
+	if provider == "AZURE" {
+		d.Set("disk_type_name", prvdr.DiskTypeName)
+	}
+	d.Set("cluster_type", clusterNormalize(
+		d.Get("cluster_type").(string), data.ClusterType))
+	if !specs && len(data.ReplicationSpecs) <= 1 && data.NumShards != 0 {
+		d.Set("num_shards", data.NumShards)
+	}
+	if !data.Shared() {
+		d.Set("disk_size_gb", int(data.DiskSizeGb))
+	}
+	if !specs && !multiRegion && data.ReplicationFactor != 0 {
+		d.Set("replication_factor", data.ReplicationFactor)
+	}
+	if specs || multiRegion {
+		d.Set("replication_spec", clusterFlattenSpecs(d, data))
+	}
+	d.Set("mongodb_version", data.MongoDbMajorVersion)
+	// Legacy backups are disabled in Atlas when cloud provider snapshots
+	// are enabled and on shared tiers, keep the configured value then
+	if !data.ProviderBackupEnabled && !data.Shared() {
+		d.Set("backup_enabled", data.BackupEnabled)
+	}
+	d.Set("provider_backup_enabled", data.ProviderBackupEnabled)
+	if !data.Shared() {
+		d.Set("auto_scaling_disk_gb_enabled",
+			data.AutoScaling.DiskGbEnabled)
+	}
+	d.Set("auto_scaling_compute_enabled", data.AutoScaling.Compute.Enabled)
+	d.Set("auto_scaling_compute_scale_down_enabled",
+		data.AutoScaling.Compute.ScaleDownEnabled)
+	if prvdr.AutoScaling != nil {
+		d.Set("auto_scaling_min_instance_size", clusterNormalize(
+			d.Get("auto_scaling_min_instance_size").(string),
+			prvdr.AutoScaling.Compute.MinInstanceSize))
+		d.Set("auto_scaling_max_instance_size", clusterNormalize(
+			d.Get("auto_scaling_max_instance_size").(string),
+			prvdr.AutoScaling.Compute.MaxInstanceSize))
+	}
+	d.Set("paused", data.Paused)
+	d.Set("termination_protection_enabled", data.TerminationProtection)
+}
+
+// clusterSetComputed sets the connection strings and state of the cluster.
+// The private connection strings are only available once a peering
+// connection exists in the cluster region.
+func clusterSetComputed(d *schema.ResourceData, data *atlas.Cluster) {
+	d.Set("mongo_uri", data.MongoUri)
+	d.Set("mongo_uri_with_options", data.MongoUriWithOptions)
+	d.Set("srv_address", data.SrvAddress)
+	d.Set("mongo_uri_private", data.ConnectionStrings.Private)
+	d.Set("srv_address_private", data.ConnectionStrings.PrivateSrv)
+	d.Set("state_name", data.StateName)
+	d.Set("shards", clusterShards(data))
+}
+
+// clusterShards groups the hosts of the cluster connection string by shard
+// using the Atlas host naming of cluster-shard-NN-MM. For sharded clusters
+// the hosts are the mongos routers running on each shard.
+func clusterShards(data *atlas.Cluster) (shards []interface{}) {
+	shards = []interface{}{}
+
+	uri, err := url.Parse(data.MongoUri)
+	if err != nil || uri.Host == "" {
 		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	}
+
+	index := map[string]map[string]interface{}{}
+
+	for _, host := range strings.Split(uri.Host, ",") {
+		name := ""
+
+		match := clusterShardHost.FindStringSubmatch(host)
+		if match != nil {
+			num, _ := strconv.Atoi(match[2])
+			name = fmt.Sprintf("%s-shard-%d", match[1], num)
 		}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Clusters request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+		shard := index[name]
+		if shard == nil {
+			shard = map[string]interface{}{
+				"name":  name,
+				"hosts": []interface{}{},
+			}
+			index[name] = shard
+			shards = append(shards, shard)
 		}
+
+		shard["hosts"] = append(shard["hosts"].([]interface{}), host)
+	}
+
+	return
+}
+
+func clusterImport(d *schema.ResourceData, m interface{}) (
+	data []*schema.ResourceData, err error) {
+
+	prvdr := m.(*schemas.Provider)
+	clnt := prvdr.Client.WithContext(prvdr.StopContext)
+
+	parts, err := parseImportId(d.Id(), 2, "group_id/name")
+	if err != nil {
 		return
 	}
 
-	data = &clusterData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
+	clstData, err := clnt.GetCluster(parts[0], parts[1])
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Clusters decode failed"),
+		return
+	}
+
+	if clstData == nil {
+		err = &errortypes.NotFoundError{
+			errors.Newf("resources: Cluster %s not found", parts[1]),
 		}
 		return
 	}
 
+	d.Set("group_id", parts[0])
+	d.Set("name", clstData.Name)
+	d.Set("adopt_existing", false)
+	clusterSetData(d, clstData)
+	clusterSetComputed(d, clstData)
+	d.SetId(clstData.Name)
+
+	data = []*schema.ResourceData{d}
+
 	return
 }
 
 func clusterCreate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutCreate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	clst := schemas.LoadCluster(d)
 
-	var clstData *clusterData
-	for {
-		clstData, err = clusterGet(prvdr, clst.GroupId, clst.Name)
+	err = clusterValidate(clst)
+	if err != nil {
+		return
+	}
+
+	clstData, err := clnt.GetCluster(clst.GroupId, clst.Name)
+	if err != nil {
+		return
+	}
+
+	// Existing clusters are only adopted when adopt_existing is set as
+	// destroying the resource deletes the cluster, an adopted cluster is
+	// updated to the configuration
+	if clstData != nil {
+		if !clst.AdoptExisting {
+			err = &errortypes.WriteError{
+				errors.Newf("resources: Cluster %s already exists, "+
+					"import it with the id %s/%s or set adopt_existing",
+					clst.Name, clst.GroupId, clst.Name),
+			}
+			return
+		}
+
+		_, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
+		if err != nil {
+			return
+		}
+
+		clstData, err = clusterPut(d, clnt, clst)
 		if err != nil {
 			return
 		}
 
 		if clstData == nil {
-			err = clusterPost(prvdr, clst)
-			if err != nil {
-				return
+			err = &errortypes.NotFoundError{
+				errors.New("resources: Cluster not found"),
 			}
-		} else if clstData.Available() {
-			break
+			return
+		}
+	} else {
+		err = clusterPost(clnt, clst)
+		if err != nil {
+			return
 		}
-
-		time.Sleep(1 * time.Second)
 	}
 
-	cntr, err := containerGet(prvdr, clst)
+	d.SetId(clst.Name)
+
+	clstData, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
 	if err != nil {
 		return
 	}
 
-	if cntr == nil {
-		err = errortypes.NotFoundError{
-			errors.New("resources: Container not found"),
+	if clst.AdvancedConfig != nil {
+		argsData, e := clusterSetProcessArgs(ctx, clnt, clst)
+		if e != nil {
+			err = e
+			return
 		}
-		return
+
+		clusterSetAdvancedConfig(d, argsData)
 	}
 
-	d.Set("container_id", cntr.Id)
-	d.Set("atlas_vpc_id", cntr.VpcId)
-	d.Set("atlas_cidr", cntr.AtlasCidrBlock)
-	d.SetId(clst.Name)
+	if clst.Paused && !clstData.Paused {
+		clstData, err = clusterPause(ctx, clnt, clst, true)
+		if err != nil {
+			return
+		}
+	}
+
+	clusterSetComputed(d, clstData)
+
+	err = clusterSetContainer(d, clnt, clst)
+	if err != nil {
+		return
+	}
 
 	return
 }
 
 func clusterRead(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutRead)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	clst := schemas.LoadCluster(d)
 
-	clstData, err := clusterGet(prvdr, clst.GroupId, clst.Name)
+	clstData, err := clnt.GetCluster(clst.GroupId, clst.Name)
 	if err != nil {
 		return
 	}
@@ -508,21 +1511,27 @@ func clusterRead(d *schema.ResourceData, m interface{}) (err error) {
 		return
 	}
 
-	cntr, err := containerGet(prvdr, clst)
-	if err != nil {
-		return
-	}
+	clusterSetData(d, clstData)
+	clusterSetComputed(d, clstData)
+	clst = schemas.LoadCluster(d)
 
-	if cntr == nil {
-		err = errortypes.NotFoundError{
-			errors.New("resources: Container not found"),
+	if clst.AdvancedConfig != nil {
+		argsData, e := clnt.GetProcessArgs(clst.GroupId, clst.Name)
+		if e != nil {
+			err = e
+			return
 		}
+
+		if argsData != nil {
+			clusterSetAdvancedConfig(d, argsData)
+		}
+	}
+
+	err = clusterSetContainer(d, clnt, clst)
+	if err != nil {
 		return
 	}
 
-	d.Set("container_id", cntr.Id)
-	d.Set("atlas_vpc_id", cntr.VpcId)
-	d.Set("atlas_cidr", cntr.AtlasCidrBlock)
 	d.SetId(clst.Name)
 
 	return
@@ -530,20 +1539,28 @@ func clusterRead(d *schema.ResourceData, m interface{}) (err error) {
 
 func clusterUpdate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutUpdate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	clst := schemas.LoadCluster(d)
 
-	clstData, err := clusterPut(prvdr, clst)
+	err = clusterValidate(clst)
 	if err != nil {
 		return
 	}
 
-	if clstData == nil {
-		d.SetId("")
-		return
+	var clstData *atlas.Cluster
+
+	// Resume before applying changes and pause after applying them
+	if d.HasChange("paused") && !clst.Paused {
+		clstData, err = clusterPause(ctx, clnt, clst, false)
+		if err != nil {
+			return
+		}
 	}
 
-	for {
-		clstData, err = clusterGet(prvdr, clst.GroupId, clst.Name)
+	if clusterHasChange(d) {
+		clstData, err = clusterPut(d, clnt, clst)
 		if err != nil {
 			return
 		}
@@ -551,34 +1568,74 @@ func clusterUpdate(d *schema.ResourceData, m interface{}) (err error) {
 		if clstData == nil {
 			d.SetId("")
 			return
-		} else if clstData.Available() {
-			break
 		}
 
-		time.Sleep(1 * time.Second)
+		clstData, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
+		if err != nil {
+			return
+		}
 	}
 
-	cntr, err := containerGet(prvdr, clst)
-	if err != nil {
-		return
+	if d.HasChange("advanced_configuration") &&
+		clst.AdvancedConfig != nil {
+
+		argsData, e := clusterSetProcessArgs(ctx, clnt, clst)
+		if e != nil {
+			err = e
+			return
+		}
+
+		clusterSetAdvancedConfig(d, argsData)
 	}
 
-	if cntr == nil {
-		err = errortypes.NotFoundError{
-			errors.New("resources: Container not found"),
+	if d.HasChange("paused") && clst.Paused {
+		clstData, err = clusterPause(ctx, clnt, clst, true)
+		if err != nil {
+			return
 		}
-		return
 	}
 
-	d.Set("container_id", cntr.Id)
-	d.Set("atlas_vpc_id", cntr.VpcId)
-	d.Set("atlas_cidr", cntr.AtlasCidrBlock)
-	d.SetId(clst.Name)
+	// Changes to only the advanced configuration do not update the
+	// cluster document
+	if clstData == nil {
+		clstData, err = clusterWait(ctx, clnt, clst.GroupId, clst.Name)
+		if err != nil {
+			return
+		}
+	}
+
+	clusterSetComputed(d, clstData)
+
+	err = clusterSetContainer(d, clnt, clst)
+	if err != nil {
+		return
+	}
 
 	return
 }
 
 func clusterDelete(d *schema.ResourceData, m interface{}) (err error) {
+	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutDelete)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
+	clst := schemas.LoadCluster(d)
+
+	if clst.TerminationProtection {
+		err = &errortypes.WriteError{
+			errors.Newf("resources: Cluster %s has termination "+
+				"protection enabled, set termination_protection_enabled "+
+				"to false before destroying", clst.Name),
+		}
+		return
+	}
+
+	err = clusterDel(ctx, clnt, clst.GroupId, clst.Name)
+	if err != nil {
+		return
+	}
+
 	d.SetId("")
+
 	return
 }
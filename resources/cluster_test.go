@@ -0,0 +1,575 @@
+package resources
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"testing"
+)
+
+func testClusterData() *atlas.Cluster {
+	return &atlas.Cluster{
+		Name:                "test",
+		ClusterType:         "REPLICASET",
+		StateName:           "IDLE",
+		MongoDbMajorVersion: "3.6",
+		NumShards:           1,
+		ReplicationFactor:   3,
+		ReplicationSpecs: []*atlas.ClusterReplicationSpec{
+			&atlas.ClusterReplicationSpec{
+				Id:        "spec",
+				NumShards: 1,
+				ZoneName:  "Zone 1",
+				RegionsConfig: map[string]atlas.ClusterRegionConfig{
+					"US_EAST_2": atlas.ClusterRegionConfig{
+						ElectableNodes: 3,
+						Priority:       7,
+					},
+				},
+			},
+		},
+		DiskSizeGb:    10,
+		BackupEnabled: true,
+		AutoScaling: atlas.ClusterAutoScaling{
+			DiskGbEnabled: true,
+		},
+		ProviderSettings: atlas.ClusterProvider{
+			ProviderName:     "AWS",
+			RegionName:       "US_EAST_2",
+			InstanceSizeName: "M10",
+		},
+	}
+}
+
+func TestClusterSetDataRegion(t *testing.T) {
+	data := testClusterData()
+	data.ReplicationFactor = 5
+	data.ProviderSettings.RegionName = "US_WEST_2"
+	rgns := data.ReplicationSpecs[0].RegionsConfig
+	delete(rgns, "US_EAST_2")
+	rgns["US_WEST_2"] = atlas.ClusterRegionConfig{
+		ElectableNodes: 5,
+		Priority:       7,
+	}
+
+	d := schema.TestResourceDataRaw(t, Cluster().Schema,
+		map[string]interface{}{
+			"group_id": "group",
+			"name":     "test",
+		})
+	clusterSetData(d, data)
+
+	if d.Get("region") != "us-west-2" || d.Get("replication_factor") != 5 {
+		t.Errorf("region %s replication_factor %d, expected us-west-2 5",
+			d.Get("region"), d.Get("replication_factor"))
+	}
+
+	// Single region replication specs do not set the region arguments
+	d = schema.TestResourceDataRaw(t, Cluster().Schema,
+		map[string]interface{}{
+			"group_id": "group",
+			"name":     "test",
+			"replication_spec": []interface{}{
+				map[string]interface{}{
+					"regions_config": []interface{}{
+						map[string]interface{}{
+							"region_name":     "us-west-2",
+							"electable_nodes": 5,
+						},
+					},
+				},
+			},
+		})
+	clusterSetData(d, data)
+
+	if d.Get("region") != "us-east-2" || d.Get("replication_factor") != 3 {
+		t.Errorf("region %s replication_factor %d, expected us-east-2 3",
+			d.Get("region"), d.Get("replication_factor"))
+	}
+
+	specs := d.Get("replication_spec").([]interface{})
+	if len(specs) != 1 {
+		t.Fatalf("replication_spec length %d, expected 1", len(specs))
+	}
+	regions := specs[0].(map[string]interface{})["regions_config"]
+	rgn := regions.([]interface{})[0].(map[string]interface{})
+	if rgn["region_name"] != "us-west-2" || rgn["electable_nodes"] != 5 {
+		t.Errorf("regions_config %v, expected us-west-2 with 5 nodes", rgn)
+	}
+}
+
+func TestClusterSetDataShards(t *testing.T) {
+	data := testClusterData()
+	data.ClusterType = "SHARDED"
+	data.NumShards = 2
+	data.ReplicationSpecs[0].NumShards = 2
+
+	d := schema.TestResourceDataRaw(t, Cluster().Schema,
+		map[string]interface{}{
+			"group_id":     "group",
+			"name":         "test",
+			"cluster_type": "SHARDED",
+			"num_shards":   2,
+		})
+	clusterSetData(d, data)
+
+	if d.Get("num_shards") != 2 {
+		t.Errorf("num_shards %d, expected 2", d.Get("num_shards"))
+	}
+
+	// Shards of replication specs do not set num_shards
+	d = schema.TestResourceDataRaw(t, Cluster().Schema,
+		map[string]interface{}{
+			"group_id":     "group",
+			"name":         "test",
+			"cluster_type": "SHARDED",
+			"replication_spec": []interface{}{
+				map[string]interface{}{
+					"num_shards": 2,
+					"regions_config": []interface{}{
+						map[string]interface{}{
+							"region_name": "us-east-2",
+						},
+					},
+				},
+			},
+		})
+	clusterSetData(d, data)
+
+	if d.Get("num_shards") != 1 {
+		t.Errorf("num_shards %d, expected 1", d.Get("num_shards"))
+	}
+
+	specs := d.Get("replication_spec").([]interface{})
+	spec := specs[0].(map[string]interface{})
+	if spec["num_shards"] != 2 {
+		t.Errorf("replication_spec num_shards %d, expected 2",
+			spec["num_shards"])
+	}
+}
+
+func TestClusterUpdateAdvancedConfig(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+		"advanced_configuration": []interface{}{
+			map[string]interface{}{
+				"no_table_scan": false,
+			},
+		},
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw["advanced_configuration"] = []interface{}{
+		map[string]interface{}{
+			"no_table_scan": true,
+		},
+	}
+
+	state, err = testApply(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["advanced_configuration.0.no_table_scan"] != "true" {
+		t.Errorf("no_table_scan %s, expected true",
+			state.Attributes["advanced_configuration.0.no_table_scan"])
+	}
+	if state.Attributes["mongo_uri"] == "" {
+		t.Error("mongo_uri not set")
+	}
+}
+
+func TestClusterProviders(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	for _, raw := range []map[string]interface{}{
+		map[string]interface{}{
+			"group_id":         groupId,
+			"name":             "aws",
+			"service_provider": "AWS",
+			"region":           "us-east-1",
+		},
+		map[string]interface{}{
+			"group_id":         groupId,
+			"name":             "gcp",
+			"service_provider": "GCP",
+			"region":           "us-central1",
+		},
+		map[string]interface{}{
+			"group_id":         groupId,
+			"name":             "azure",
+			"service_provider": "AZURE",
+			"region":           "eastus2",
+		},
+	} {
+		state, err := testApply(t, Cluster(), prvdr, nil, raw)
+		if err != nil {
+			t.Errorf("%s create failed %s", raw["name"], err)
+			continue
+		}
+
+		if state.Attributes["container_id"] == "" ||
+			state.Attributes["atlas_vpc_id"] == "" {
+
+			t.Errorf("%s container not set", raw["name"])
+		}
+
+		diff, err := testDiff(t, Cluster(), prvdr, state, raw)
+		if err != nil {
+			t.Errorf("%s plan failed %s", raw["name"], err)
+			continue
+		}
+
+		if diff != nil && !diff.Empty() {
+			t.Errorf("%s plan not empty %#v", raw["name"], diff.Attributes)
+		}
+	}
+}
+
+func TestClusterEncryptEbsVolume(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["encrypt_ebs_volume"] != "true" {
+		t.Errorf("encrypt_ebs_volume %s, expected true",
+			state.Attributes["encrypt_ebs_volume"])
+	}
+
+	raw["encrypt_ebs_volume"] = false
+
+	state, err = testApply(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clstData, err := prvdr.Client.GetCluster(groupId, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypt := clstData.ProviderSettings.EncryptEbsVolume
+	if encrypt == nil || *encrypt {
+		t.Error("encryptEBSVolume not disabled")
+	}
+	if state.Attributes["encrypt_ebs_volume"] != "false" {
+		t.Errorf("encrypt_ebs_volume %s, expected false",
+			state.Attributes["encrypt_ebs_volume"])
+	}
+}
+
+func TestClusterSharedUpgrade(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id":     groupId,
+		"name":         "test",
+		"size":         "M2",
+		"disk_size_gb": 20,
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw["size"] = "M10"
+
+	state, err = testApply(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clstData, err := prvdr.Client.GetCluster(groupId, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clstData.DiskSizeGb != 20 || !clstData.BackupEnabled ||
+		!clstData.AutoScaling.DiskGbEnabled {
+
+		t.Errorf("cluster disk %.0f backup %t disk autoscaling %t, "+
+			"expected 20 true true", clstData.DiskSizeGb,
+			clstData.BackupEnabled, clstData.AutoScaling.DiskGbEnabled)
+	}
+
+	diff, err := testDiff(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && !diff.Empty() {
+		t.Errorf("plan not empty %#v", diff.Attributes)
+	}
+}
+
+func TestClusterReplaceProvider(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id":         groupId,
+		"name":             "test",
+		"service_provider": "AWS",
+		"region":           "us-east-1",
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["disk_iops"] != "100" ||
+		state.Attributes["volume_type"] != "STANDARD" {
+
+		t.Errorf("disk_iops %s volume_type %s, expected 100 STANDARD",
+			state.Attributes["disk_iops"], state.Attributes["volume_type"])
+	}
+
+	for _, provider := range []map[string]interface{}{
+		map[string]interface{}{
+			"service_provider": "GCP",
+			"region":           "us-central1",
+			"disk_iops":        nil,
+		},
+		map[string]interface{}{
+			"service_provider": "AZURE",
+			"region":           "eastus2",
+			"disk_type_name":   "P4",
+		},
+		map[string]interface{}{
+			"service_provider": "AWS",
+			"region":           "us-east-1",
+			"disk_type_name":   nil,
+			"disk_iops":        200,
+		},
+	} {
+		for key, val := range provider {
+			if val == nil {
+				delete(raw, key)
+			} else {
+				raw[key] = val
+			}
+		}
+
+		diff, err := testDiff(t, Cluster(), prvdr, state, raw)
+		if err != nil {
+			t.Fatalf("%s plan failed %s", raw["service_provider"], err)
+		}
+
+		if !diff.RequiresNew() {
+			t.Errorf("%s plan does not replace the cluster",
+				raw["service_provider"])
+		}
+
+		state, err = testApply(t, Cluster(), prvdr, state, raw)
+		if err != nil {
+			t.Fatalf("%s replace failed %s", raw["service_provider"], err)
+		}
+
+		clstData, err := prvdr.Client.GetCluster(groupId, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if clstData.Provider() != raw["service_provider"] {
+			t.Errorf("cluster provider %s, expected %s",
+				clstData.Provider(), raw["service_provider"])
+		}
+	}
+
+	// Provider specific arguments are still checked against the provider
+	raw["service_provider"] = "GCP"
+	raw["region"] = "us-central1"
+
+	_, err = testDiff(t, Cluster(), prvdr, state, raw)
+	if err == nil {
+		t.Error("plan with disk_iops on GCP expected error")
+	}
+}
+
+func TestClusterImportProviderCase(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	_, err := prvdr.Client.CreateCluster(groupId, &atlas.ClusterPost{
+		Name:        "test",
+		ClusterType: "REPLICASET",
+		DiskSizeGb:  10,
+		ProviderSettings: atlas.ClusterProvider{
+			ProviderName:     "AWS",
+			RegionName:       "US_EAST_1",
+			InstanceSizeName: "M10",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := Cluster().Data(nil)
+	d.SetId(groupId + "/test")
+
+	data, err := Cluster().Importer.State(d, prvdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := Cluster().Refresh(data[0].State(), prvdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["service_provider"] != "AWS" {
+		t.Errorf("service_provider %s, expected AWS",
+			state.Attributes["service_provider"])
+	}
+
+	diff, err := testDiff(t, Cluster(), prvdr, state,
+		map[string]interface{}{
+			"group_id":         groupId,
+			"name":             "test",
+			"service_provider": "aws",
+			"region":           "us-east-1",
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && diff.RequiresNew() {
+		t.Errorf("plan replaces the cluster %#v", diff.Attributes)
+	}
+}
+
+func TestClusterDiskAutoScaling(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id":     groupId,
+		"name":         "test",
+		"disk_size_gb": 40,
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Atlas grows the disk with disk autoscaling
+	_, err = prvdr.Client.UpdateCluster(groupId, "test", &atlas.ClusterPut{
+		DiskSizeGb: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = Cluster().Refresh(state, prvdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := testDiff(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && !diff.Empty() {
+		t.Errorf("plan not empty %#v", diff.Attributes)
+	}
+
+	// Without disk autoscaling the disk cannot be reduced
+	raw["auto_scaling_disk_gb_enabled"] = false
+
+	_, err = testDiff(t, Cluster(), prvdr, state, raw)
+	if err == nil {
+		t.Error("plan reducing disk_size_gb expected error")
+	}
+}
+
+func TestClusterPauseShared(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	_, err := testDiff(t, Cluster(), prvdr, nil, map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+		"size":     "M2",
+		"paused":   true,
+	})
+	if err == nil {
+		t.Error("plan pausing a shared tier expected error")
+	}
+
+	clsts, err := prvdr.Client.ListClusters(groupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clsts) != 0 {
+		t.Errorf("%d clusters created, expected 0", len(clsts))
+	}
+}
+
+func TestClusterPausedChange(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"group_id":         groupId,
+		"name":             "test",
+		"service_provider": "AWS",
+		"region":           "us-east-1",
+		"paused":           true,
+	}
+
+	state, err := testApply(t, Cluster(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes suppressed in the plan are allowed
+	raw["service_provider"] = "aws"
+
+	_, err = testDiff(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Errorf("plan with provider case error %s", err)
+	}
+
+	raw["disk_size_gb"] = 20
+
+	_, err = testDiff(t, Cluster(), prvdr, state, raw)
+	if err == nil {
+		t.Error("plan changing a paused cluster expected error")
+	}
+
+	raw["paused"] = false
+
+	state, err = testApply(t, Cluster(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["disk_size_gb"] != "20" ||
+		state.Attributes["paused"] != "false" {
+
+		t.Errorf("disk_size_gb %s paused %s, expected 20 false",
+			state.Attributes["disk_size_gb"], state.Attributes["paused"])
+	}
+}
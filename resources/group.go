@@ -1,216 +1,257 @@
 package resources
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
-	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
 	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
-	"io/ioutil"
-	"net/http"
+	"strings"
+	"time"
 )
 
 func Group() *schema.Resource {
 	return &schema.Resource{
 		Create: groupCreate,
 		Read:   groupRead,
+		Update: groupUpdate,
 		Delete: groupDelete,
+		Importer: &schema.ResourceImporter{
+			State: groupImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			"force_destroy": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"adopt_existing": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
-type groupPostData struct {
-	Name  string `json:"name"`
-	OrgId string `json:"orgId"`
-}
-
-type groupData struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-}
+func groupCreate(d *schema.ResourceData, m interface{}) (err error) {
+	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutCreate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
+	grp := schemas.LoadGroup(d)
 
-func groupGet(prvdr *schemas.Provider, name string) (
-	data *groupData, err error) {
-
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/byName/%s",
-			name,
-		),
-		nil,
-	)
+	grpData, err := clnt.GetGroupByName(grp.Name)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Group request failed"),
-		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Group request failed"),
+	// Existing groups are only adopted when adopt_existing is set as
+	// destroying the resource deletes the group
+	if grpData != nil {
+		if !grp.AdoptExisting {
+			err = &errortypes.WriteError{
+				errors.Newf("resources: Group %s already exists, import "+
+					"it with the id %s or set adopt_existing",
+					grp.Name, grpData.Id),
+			}
+			return
+		}
+	} else {
+		grpData, err = clnt.CreateGroup(&atlas.GroupPost{
+			Name:  grp.Name,
+			OrgId: prvdr.OrgId,
+		})
+		if err != nil {
+			return
 		}
-		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 || resp.StatusCode == 401 {
-		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+	d.SetId(grpData.Id)
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Group request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
+	return
+}
+
+func groupRead(d *schema.ResourceData, m interface{}) (err error) {
+	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutRead)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
+	grp := schemas.LoadGroup(d)
+
+	grpData, err := clnt.GetGroupByName(grp.Name)
+	if err != nil {
 		return
 	}
 
-	data = &groupData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Group decode failed"),
-		}
+	if grpData == nil {
+		d.SetId("")
 		return
 	}
 
+	d.SetId(grpData.Id)
+
 	return
 }
 
-func groupPost(prvdr *schemas.Provider, grp *schemas.Group) (
-	data *groupData, err error) {
+func groupImport(d *schema.ResourceData, m interface{}) (
+	data []*schema.ResourceData, err error) {
 
-	postData := groupPostData{
-		Name:  grp.Name,
-		OrgId: prvdr.OrgId,
-	}
+	prvdr := m.(*schemas.Provider)
+	clnt := prvdr.Client.WithContext(prvdr.StopContext)
 
-	body, err := json.Marshal(postData)
+	grpData, err := clnt.GetGroup(d.Id())
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Group marshal failed"),
-		}
 		return
 	}
 
-	req, err := http.NewRequest(
-		"POST",
-		constants.BaseUrl+"/api/atlas/v1.0/groups",
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Groups request failed"),
+	if grpData == nil {
+		err = &errortypes.NotFoundError{
+			errors.Newf("resources: Group %s not found", d.Id()),
 		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	d.Set("name", grpData.Name)
+	d.Set("force_destroy", false)
+	d.Set("adopt_existing", false)
+	d.SetId(grpData.Id)
+
+	data = []*schema.ResourceData{d}
+
+	return
+}
+
+func groupUpdate(d *schema.ResourceData, m interface{}) (err error) {
+	return
+}
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+// groupChildren returns descriptions of the clusters, peers and whitelist
+// entries that prevent the group from being deleted.
+func groupChildren(clnt *atlas.Client, groupId string) (
+	clsts []*atlas.Cluster, prs []*atlas.Peer,
+	entries []*atlas.WhitelistEntry, children []string, err error) {
+
+	allClsts, err := clnt.ListClusters(groupId)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Groups request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	// Deleted clusters are listed until Atlas removes them
+	for _, clst := range allClsts {
+		if !clst.Deleted() {
+			clsts = append(clsts, clst)
 		}
+	}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Groups request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
+	prs, err = clnt.ListPeers(groupId)
+	if err != nil {
 		return
 	}
 
-	data = &groupData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
+	entries, err = clnt.ListWhitelistEntries(groupId)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Group decode failed"),
-		}
 		return
 	}
 
+	for _, clst := range clsts {
+		children = append(children, "cluster "+clst.Name)
+	}
+	for _, pr := range prs {
+		children = append(children, "peer "+pr.Id)
+	}
+	for _, entry := range entries {
+		children = append(children, "whitelist "+entry.CidrBlock)
+	}
+
 	return
 }
 
-func groupCreate(d *schema.ResourceData, m interface{}) (err error) {
-	prvdr := m.(*schemas.Provider)
-	grp := schemas.LoadGroup(d)
+// groupClean deletes all clusters, peers and whitelist entries in the
+// group and waits for the clusters and peers to be removed.
+func groupClean(ctx context.Context, clnt *atlas.Client, groupId string,
+	clsts []*atlas.Cluster, prs []*atlas.Peer,
+	entries []*atlas.WhitelistEntry) (err error) {
+
+	for _, clst := range clsts {
+		if clst.TerminationProtection {
+			err = &errortypes.WriteError{
+				errors.Newf("resources: Cluster %s has termination "+
+					"protection enabled", clst.Name),
+			}
+			return
+		}
+	}
 
-	grpData, err := groupGet(prvdr, grp.Name)
-	if err != nil {
-		return
+	for _, entry := range entries {
+		err = clnt.DeleteWhitelistEntry(groupId, entry.CidrBlock)
+		if err != nil {
+			return
+		}
 	}
 
-	if grpData == nil {
-		grpData, err = groupPost(prvdr, grp)
+	for _, pr := range prs {
+		err = peerDel(ctx, clnt, groupId, pr.Id)
 		if err != nil {
 			return
 		}
 	}
 
-	d.SetId(grpData.Id)
+	for _, clst := range clsts {
+		err = clusterDel(ctx, clnt, groupId, clst.Name)
+		if err != nil {
+			return
+		}
+	}
 
 	return
 }
 
-func groupRead(d *schema.ResourceData, m interface{}) (err error) {
+func groupDelete(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
-	clst := schemas.LoadGroup(d)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutDelete)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
+	grp := schemas.LoadGroup(d)
 
-	grpData, err := groupGet(prvdr, clst.Name)
+	clsts, prs, entries, children, err := groupChildren(clnt, grp.Id)
 	if err != nil {
 		return
 	}
 
-	if grpData == nil {
-		d.SetId("")
-		return
-	}
+	if len(children) > 0 {
+		if !grp.ForceDestroy {
+			err = &errortypes.WriteError{
+				errors.Newf("resources: Group %s cannot be deleted "+
+					"while it contains %s, delete them or set "+
+					"force_destroy", grp.Name,
+					strings.Join(children, ", ")),
+			}
+			return
+		}
 
-	d.SetId(grpData.Id)
+		err = groupClean(ctx, clnt, grp.Id, clsts, prs, entries)
+		if err != nil {
+			return
+		}
+	}
 
-	return
-}
+	err = clnt.DeleteGroup(grp.Id)
+	if err != nil {
+		return
+	}
 
-func groupDelete(d *schema.ResourceData, m interface{}) (err error) {
 	d.SetId("")
+
 	return
 }
@@ -0,0 +1,51 @@
+package resources
+
+import (
+	"github.com/hashicorp/terraform/terraform"
+	"testing"
+)
+
+func TestGroupDeleteDeletedClusters(t *testing.T) {
+	prvdr, srv, _ := testProvider(t)
+	defer srv.Close()
+
+	grpState, err := testApply(t, Group(), prvdr, nil,
+		map[string]interface{}{
+			"name": "group",
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clstState, err := testApply(t, Cluster(), prvdr, nil,
+		map[string]interface{}{
+			"group_id": grpState.ID,
+			"name":     "test",
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destroy := &terraform.InstanceDiff{
+		Destroy: true,
+	}
+
+	// The cluster is listed as deleted when the group is destroyed
+	_, err = Cluster().Apply(clstState, destroy, prvdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Group().Apply(grpState, destroy, prvdr)
+	if err != nil {
+		t.Fatalf("group delete failed %s", err)
+	}
+
+	grp, err := prvdr.Client.GetGroup(grpState.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grp != nil {
+		t.Error("group not deleted")
+	}
+}
@@ -0,0 +1,34 @@
+package resources
+
+import (
+	"github.com/dropbox/godropbox/errors"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"strings"
+)
+
+// parseImportId splits a composite import id into n parts, the last part
+// may contain slashes such as a CIDR block.
+func parseImportId(id string, n int, format string) (
+	parts []string, err error) {
+
+	parts = strings.SplitN(id, "/", n)
+	if len(parts) != n {
+		err = &errortypes.ParseError{
+			errors.Newf("resources: Invalid import id '%s', expected %s",
+				id, format),
+		}
+		return
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Invalid import id '%s', "+
+					"expected %s", id, format),
+			}
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,42 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseImportId(t *testing.T) {
+	tests := []struct {
+		id    string
+		n     int
+		parts []string
+	}{
+		{"group/cluster", 2, []string{"group", "cluster"}},
+		{"group/10.0.0.0/16", 2, []string{"group", "10.0.0.0/16"}},
+		{"group/admin/user", 3, []string{"group", "admin", "user"}},
+		{"group", 2, nil},
+		{"group/", 2, nil},
+		{"/cluster", 2, nil},
+		{"", 2, nil},
+		{"group/user", 3, nil},
+	}
+
+	for _, test := range tests {
+		parts, err := parseImportId(test.id, test.n, "format")
+		if test.parts == nil {
+			if err == nil {
+				t.Errorf("%q expected error", test.id)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q error %s", test.id, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(parts, test.parts) {
+			t.Errorf("%q parts %v, expected %v", test.id, parts, test.parts)
+		}
+	}
+}
@@ -1,17 +1,12 @@
 package resources
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
-	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
 	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
-	"io/ioutil"
-	"net/http"
 	"time"
 )
 
@@ -21,6 +16,14 @@ func Peer() *schema.Resource {
 		Read:   peerRead,
 		Update: peerUpdate,
 		Delete: peerDelete,
+		Importer: &schema.ResourceImporter{
+			State: peerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"group_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -52,118 +55,15 @@ func Peer() *schema.Resource {
 	}
 }
 
-type peerPostData struct {
-	VpcId               string `json:"vpcId"`
-	AwsAccountId        string `json:"awsAccountId"`
-	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
-	ContainerId         string `json:"containerId"`
-}
-
-type peerPutData struct {
-	VpcId               string `json:"vpcId"`
-	AwsAccountId        string `json:"awsAccountId"`
-	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
-	ContainerId         string `json:"containerId"`
-}
-
-type peerData struct {
-	Id                  string `json:"id"`
-	VpcId               string `json:"vpcId"`
-	AwsAccountId        string `json:"awsAccountId"`
-	ConnectionId        string `json:"connectionId"`
-	RouteTableCidrBlock string `json:"routeTableCidrBlock"`
-	ContainerId         string `json:"containerId"`
-	StatusName          string `json:"statusName"`
-	ErrorStateName      string `json:"errorStateName"`
-}
-
-type peerResp struct {
-	Results []*peerData `json:"results"`
-}
-
-func (p *peerData) Available() bool {
-	switch p.StatusName {
-	case "PENDING_ACCEPTANCE", "FINALIZING", "AVAILABLE":
-		return true
-	default:
-		return false
-	}
-}
-
-func (p *peerData) Failed() bool {
-	switch p.StatusName {
-	case "FAILED", "TERMINATING":
-		return true
-	}
-
-	if p.ErrorStateName != "" {
-		return true
-	}
-
-	return false
-}
-
-func peerFind(prvdr *schemas.Provider, pr *schemas.Peer) (
-	data *peerData, err error) {
-
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/peers",
-			pr.GroupId,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
-
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
-
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
+func peerFind(clnt *atlas.Client, pr *schemas.Peer) (
+	data *atlas.Peer, err error) {
 
-	respData := &peerResp{}
-	err = json.NewDecoder(resp.Body).Decode(respData)
+	prs, err := clnt.ListPeers(pr.GroupId)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer decode failed"),
-		}
 		return
 	}
 
-	if respData.Results == nil {
-		return
-	}
-
-	for _, p := range respData.Results {
+	for _, p := range prs {
 		if p.VpcId == pr.VpcId {
 			data = p
 			return
@@ -173,321 +73,180 @@ func peerFind(prvdr *schemas.Provider, pr *schemas.Peer) (
 	return
 }
 
-func peerGet(prvdr *schemas.Provider, pr *schemas.Peer) (
-	data *peerData, err error) {
-
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/peers/%s",
-			pr.GroupId,
-			pr.Id,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
-
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+func peerPost(clnt *atlas.Client, pr *schemas.Peer) (
+	data *atlas.Peer, err error) {
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
+	postData := &atlas.PeerPost{
+		VpcId:               pr.VpcId,
+		AwsAccountId:        pr.AwsAccountId,
+		RouteTableCidrBlock: pr.VpcCidr,
+		ContainerId:         pr.ContainerId,
 	}
 
-	data = &peerData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
+	data, err = clnt.CreatePeer(pr.GroupId, postData)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer decode failed"),
-		}
 		return
 	}
 
 	return
 }
 
-func peerPost(prvdr *schemas.Provider, pr *schemas.Peer) (
-	data *peerData, err error) {
+func peerPut(clnt *atlas.Client, pr *schemas.Peer) (
+	data *atlas.Peer, err error) {
 
-	postData := peerPostData{
+	putData := &atlas.PeerPut{
 		VpcId:               pr.VpcId,
 		AwsAccountId:        pr.AwsAccountId,
 		RouteTableCidrBlock: pr.VpcCidr,
-		ContainerId:         pr.ContainerId,
 	}
 
-	body, err := json.Marshal(postData)
+	data, err = clnt.UpdatePeer(pr.GroupId, pr.Id, putData)
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer marshal failed"),
-		}
 		return
 	}
 
-	req, err := http.NewRequest(
-		"POST",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/peers",
-			pr.GroupId,
-		),
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
+	return
+}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+// peerDel deletes a peering connection and waits until it is removed.
+func peerDel(ctx context.Context, clnt *atlas.Client,
+	groupId, peerId string) (err error) {
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+	err = clnt.DeletePeer(groupId, peerId)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+	err = waitFor(ctx, "peer "+peerId+" deletion", func() (
+		state string, done bool, err error) {
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+		prData, err := clnt.GetPeer(groupId, peerId)
+		if err != nil {
+			return
 		}
-		return
-	}
 
-	data = &peerData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer decode failed"),
+		if prData == nil {
+			state = "DELETED"
+			done = true
+			return
 		}
-		return
-	}
-
-	return
-}
 
-func peerPut(prvdr *schemas.Provider, pr *schemas.Peer) (
-	data *peerData, err error) {
+		state = prData.StatusName
 
-	putData := peerPutData{
-		VpcId:               pr.VpcId,
-		AwsAccountId:        pr.AwsAccountId,
-		RouteTableCidrBlock: pr.VpcCidr,
-	}
-
-	body, err := json.Marshal(putData)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer marshal failed"),
-		}
 		return
-	}
-
-	req, err := http.NewRequest(
-		"PATCH",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/peers/%s",
-			pr.GroupId,
-			pr.Id,
-		),
-		bytes.NewBuffer(body),
-	)
+	})
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
-		return
-	}
-	defer resp.Body.Close()
+	return
+}
 
-	if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+func peerImport(d *schema.ResourceData, m interface{}) (
+	data []*schema.ResourceData, err error) {
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
+	prvdr := m.(*schemas.Provider)
+	clnt := prvdr.Client.WithContext(prvdr.StopContext)
 
-	data = &peerData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
+	parts, err := parseImportId(d.Id(), 2, "group_id/peer_id")
 	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Peer decode failed"),
-		}
 		return
 	}
 
-	return
-}
-
-func peerDel(prvdr *schemas.Provider, pr *schemas.Peer) (
-	err error) {
-
-	req, err := http.NewRequest(
-		"DELETE",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/peers/%s",
-			pr.GroupId,
-			pr.Id,
-		),
-		nil,
-	)
+	prData, err := clnt.GetPeer(parts[0], parts[1])
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
-		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Peer request failed"),
+	if prData == nil {
+		err = &errortypes.NotFoundError{
+			errors.Newf("resources: Peer %s not found", parts[1]),
 		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 && resp.StatusCode != 202 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+	d.Set("group_id", parts[0])
+	d.Set("container_id", prData.ContainerId)
+	d.Set("aws_account_id", prData.AwsAccountId)
+	d.Set("vpc_id", prData.VpcId)
+	d.Set("vpc_cidr", prData.RouteTableCidrBlock)
+	d.Set("connection_id", prData.ConnectionId)
+	d.SetId(prData.Id)
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
+	data = []*schema.ResourceData{d}
 
 	return
 }
 
 func peerCreate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutCreate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	pr := schemas.LoadPeer(d)
 
-	prData, err := peerFind(prvdr, pr)
+	prData, err := peerFind(clnt, pr)
 	if err != nil {
 		return
 	}
 
 	if prData == nil {
-		prData, err = peerPost(prvdr, pr)
+		prData, err = peerPost(clnt, pr)
 		if err != nil {
 			return
 		}
 	}
 
 	pr.Id = prData.Id
+	d.SetId(prData.Id)
 
-	for {
-		prData, err = peerGet(prvdr, pr)
+	err = waitFor(ctx, "peer "+pr.Id, func() (
+		state string, done bool, err error) {
+
+		prData, err = clnt.GetPeer(pr.GroupId, pr.Id)
 		if err != nil {
 			return
 		}
 
-		if prData.Available() {
-			break
+		if prData == nil {
+			err = &errortypes.NotFoundError{
+				errors.New("resources: Peer not found"),
+			}
+			return
 		}
 
+		state = prData.StatusName
+
 		if prData.Failed() {
 			err = &errortypes.RequestError{
-				errors.Wrap(err, "resources: Peer in failed state"),
+				errors.Newf("resources: Peer in failed state %s %s",
+					prData.StatusName, prData.ErrorStateName),
 			}
 			return
 		}
 
-		time.Sleep(1 * time.Second)
+		done = prData.Available()
+
+		return
+	})
+	if err != nil {
+		return
 	}
 
 	d.Set("connection_id", prData.ConnectionId)
-	d.SetId(prData.Id)
 
 	return
 }
 
 func peerRead(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutRead)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	pr := schemas.LoadPeer(d)
 
 	if pr.Id != "" {
-		prData, e := peerGet(prvdr, pr)
+		prData, e := clnt.GetPeer(pr.GroupId, pr.Id)
 		if e != nil {
 			err = e
 			return
@@ -495,7 +254,7 @@ func peerRead(d *schema.ResourceData, m interface{}) (err error) {
 
 		if prData != nil {
 			if prData.Failed() {
-				err = peerDel(prvdr, pr)
+				err = clnt.DeletePeer(pr.GroupId, pr.Id)
 				if err != nil {
 					return
 				}
@@ -515,10 +274,13 @@ func peerRead(d *schema.ResourceData, m interface{}) (err error) {
 
 func peerUpdate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutUpdate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	pr := schemas.LoadPeer(d)
 
 	if pr.Id != "" {
-		prData, e := peerPut(prvdr, pr)
+		prData, e := peerPut(clnt, pr)
 		if e != nil {
 			err = e
 			return
@@ -545,10 +307,13 @@ func peerUpdate(d *schema.ResourceData, m interface{}) (err error) {
 
 func peerDelete(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutDelete)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	pr := schemas.LoadPeer(d)
 
 	if pr.Id != "" {
-		err = peerDel(prvdr, pr)
+		err = clnt.DeletePeer(pr.GroupId, pr.Id)
 		if err != nil {
 			return
 		}
@@ -0,0 +1,152 @@
+package resources
+
+import (
+	"strings"
+)
+
+// Regions in the provider format mapped to the Atlas region names.
+var awsRegions = map[string]string{
+	"us-east-1":      "US_EAST_1",
+	"us-east-2":      "US_EAST_2",
+	"us-west-1":      "US_WEST_1",
+	"us-west-2":      "US_WEST_2",
+	"ca-central-1":   "CA_CENTRAL_1",
+	"sa-east-1":      "SA_EAST_1",
+	"eu-west-1":      "EU_WEST_1",
+	"eu-west-2":      "EU_WEST_2",
+	"eu-west-3":      "EU_WEST_3",
+	"eu-central-1":   "EU_CENTRAL_1",
+	"eu-north-1":     "EU_NORTH_1",
+	"ap-east-1":      "AP_EAST_1",
+	"ap-northeast-1": "AP_NORTHEAST_1",
+	"ap-northeast-2": "AP_NORTHEAST_2",
+	"ap-south-1":     "AP_SOUTH_1",
+	"ap-southeast-1": "AP_SOUTHEAST_1",
+	"ap-southeast-2": "AP_SOUTHEAST_2",
+	"me-south-1":     "ME_SOUTH_1",
+}
+
+var gcpRegions = map[string]string{
+	"us-central1":             "CENTRAL_US",
+	"us-east1":                "EASTERN_US",
+	"us-east4":                "US_EAST_4",
+	"us-west1":                "WESTERN_US",
+	"us-west2":                "US_WEST_2",
+	"northamerica-northeast1": "NORTH_AMERICA_NORTHEAST_1",
+	"southamerica-east1":      "SOUTH_AMERICA_EAST_1",
+	"europe-west1":            "WESTERN_EUROPE",
+	"europe-west2":            "EUROPE_WEST_2",
+	"europe-west3":            "EUROPE_WEST_3",
+	"europe-west4":            "EUROPE_WEST_4",
+	"europe-north1":           "EUROPE_NORTH_1",
+	"asia-east1":              "EASTERN_ASIA_PACIFIC",
+	"asia-east2":              "ASIA_EAST_2",
+	"asia-northeast1":         "NORTHEASTERN_ASIA_PACIFIC",
+	"asia-south1":             "ASIA_SOUTH_1",
+	"asia-southeast1":         "SOUTHEASTERN_ASIA_PACIFIC",
+	"australia-southeast1":    "AUSTRALIA_SOUTHEAST_1",
+}
+
+var azureRegions = map[string]string{
+	"eastus":             "US_EAST",
+	"eastus2":            "US_EAST_2",
+	"centralus":          "US_CENTRAL",
+	"northcentralus":     "US_NORTH_CENTRAL",
+	"southcentralus":     "US_SOUTH_CENTRAL",
+	"westus":             "US_WEST",
+	"westus2":            "US_WEST_2",
+	"westcentralus":      "US_WEST_CENTRAL",
+	"canadacentral":      "CANADA_CENTRAL",
+	"canadaeast":         "CANADA_EAST",
+	"brazilsouth":        "BRAZIL_SOUTH",
+	"northeurope":        "EUROPE_NORTH",
+	"westeurope":         "EUROPE_WEST",
+	"uksouth":            "UK_SOUTH",
+	"ukwest":             "UK_WEST",
+	"francecentral":      "FRANCE_CENTRAL",
+	"eastasia":           "ASIA_EAST",
+	"southeastasia":      "ASIA_SOUTH_EAST",
+	"japaneast":          "JAPAN_EAST",
+	"japanwest":          "JAPAN_WEST",
+	"australiaeast":      "AUSTRALIA_EAST",
+	"australiasoutheast": "AUSTRALIA_SOUTH_EAST",
+	"centralindia":       "INDIA_CENTRAL",
+	"southindia":         "INDIA_SOUTH",
+	"koreacentral":       "KOREA_CENTRAL",
+}
+
+var providerRegions = map[string]map[string]string{
+	"AWS":   awsRegions,
+	"GCP":   gcpRegions,
+	"AZURE": azureRegions,
+}
+
+// Dedicated instance sizes available on each provider.
+var providerSizes = map[string]map[string]bool{
+	"AWS": {
+		"M10": true, "M20": true, "M30": true, "M40": true, "M50": true,
+		"M60": true, "M80": true, "M100": true, "M140": true,
+		"M200": true, "M300": true, "M400": true, "M700": true,
+		"R40": true, "R50": true, "R60": true, "R80": true, "R200": true,
+		"R300": true, "R400": true, "R700": true,
+		"M40_NVME": true, "M50_NVME": true, "M60_NVME": true,
+		"M80_NVME": true, "M200_NVME": true, "M400_NVME": true,
+	},
+	"GCP": {
+		"M10": true, "M20": true, "M30": true, "M40": true, "M50": true,
+		"M60": true, "M80": true, "M140": true, "M200": true,
+		"M300": true, "M400": true,
+		"R40": true, "R50": true, "R60": true, "R80": true, "R200": true,
+		"R300": true, "R400": true,
+	},
+	"AZURE": {
+		"M10": true, "M20": true, "M30": true, "M40": true, "M50": true,
+		"M60": true, "M80": true, "M90": true, "M200": true,
+		"R40": true, "R50": true, "R60": true, "R80": true, "R200": true,
+		"R300": true, "R400": true,
+	},
+}
+
+// regionAtlas returns the Atlas name of a region given in either the
+// provider or the Atlas format and whether the region is known.
+func regionAtlas(provider, region string) (name string, ok bool) {
+	regions := providerRegions[strings.ToUpper(provider)]
+
+	name, ok = regions[strings.ToLower(region)]
+	if ok {
+		return
+	}
+
+	name = strings.Replace(strings.ToUpper(region), "-", "_", -1)
+	for _, n := range regions {
+		if n == name {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
+
+// regionProvider returns the provider format of an Atlas region name.
+func regionProvider(provider, name string) string {
+	for region, n := range providerRegions[strings.ToUpper(provider)] {
+		if n == name {
+			return region
+		}
+	}
+
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// regionNormalize returns the current region when it refers to the Atlas
+// region name to prevent a diff between the two formats.
+func regionNormalize(provider, cur, name string) string {
+	if cur != "" {
+		if curName, _ := regionAtlas(provider, cur); curName == name {
+			return cur
+		}
+	}
+
+	return regionProvider(provider, name)
+}
@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"testing"
+)
+
+func TestRegionAtlas(t *testing.T) {
+	tests := []struct {
+		provider string
+		region   string
+		name     string
+		ok       bool
+	}{
+		{"AWS", "us-east-1", "US_EAST_1", true},
+		{"aws", "US-EAST-1", "US_EAST_1", true},
+		{"AWS", "US_EAST_1", "US_EAST_1", true},
+		{"AWS", "us_east_1", "US_EAST_1", true},
+		{"GCP", "us-central1", "CENTRAL_US", true},
+		{"GCP", "CENTRAL_US", "CENTRAL_US", true},
+		{"AZURE", "eastus2", "US_EAST_2", true},
+		{"AZURE", "US_EAST_2", "US_EAST_2", true},
+		{"GCP", "us-east-1", "US_EAST_1", false},
+		{"AWS", "mars-1", "MARS_1", false},
+		{"TENANT", "us-east-1", "US_EAST_1", false},
+	}
+
+	for _, test := range tests {
+		name, ok := regionAtlas(test.provider, test.region)
+		if name != test.name || ok != test.ok {
+			t.Errorf("%s %s region %s %t, expected %s %t", test.provider,
+				test.region, name, ok, test.name, test.ok)
+		}
+	}
+}
+
+func TestRegionProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		name     string
+		region   string
+	}{
+		{"AWS", "US_EAST_1", "us-east-1"},
+		{"GCP", "CENTRAL_US", "us-central1"},
+		{"AZURE", "US_EAST_2", "eastus2"},
+		{"aws", "US_WEST_2", "us-west-2"},
+		{"AWS", "MARS_1", "mars-1"},
+	}
+
+	for _, test := range tests {
+		region := regionProvider(test.provider, test.name)
+		if region != test.region {
+			t.Errorf("%s %s region %s, expected %s", test.provider,
+				test.name, region, test.region)
+		}
+	}
+}
+
+func TestRegionNormalize(t *testing.T) {
+	tests := []struct {
+		provider string
+		cur      string
+		name     string
+		region   string
+	}{
+		{"AWS", "", "US_EAST_1", "us-east-1"},
+		{"AWS", "US_EAST_1", "US_EAST_1", "US_EAST_1"},
+		{"AWS", "us-east-1", "US_EAST_1", "us-east-1"},
+		{"AWS", "us-east-1", "US_WEST_2", "us-west-2"},
+		{"GCP", "CENTRAL_US", "CENTRAL_US", "CENTRAL_US"},
+		{"GCP", "us-east1", "CENTRAL_US", "us-central1"},
+	}
+
+	for _, test := range tests {
+		region := regionNormalize(test.provider, test.cur, test.name)
+		if region != test.region {
+			t.Errorf("%s %s %s region %s, expected %s", test.provider,
+				test.cur, test.name, region, test.region)
+		}
+	}
+}
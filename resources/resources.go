@@ -1,12 +0,0 @@
-package resources
-
-import (
-	"net/http"
-	"time"
-)
-
-var (
-	client = &http.Client{
-		Timeout: 20 * time.Second,
-	}
-)
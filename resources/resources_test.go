@@ -0,0 +1,109 @@
+package resources
+
+import (
+	"context"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlastest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
+	"net/http"
+	"testing"
+)
+
+// testProvider returns a provider meta using a fake Atlas server with a
+// group, objects advance through their states on every read.
+func testProvider(t *testing.T) (prvdr *schemas.Provider,
+	srv *atlastest.Server, groupId string) {
+
+	srv = atlastest.NewServer("user", "key")
+	srv.Transitions = 0
+
+	prvdr = &schemas.Provider{
+		Username: srv.Username,
+		ApiKey:   srv.ApiKey,
+		OrgId:    "org",
+		BaseUrl:  srv.URL,
+		Client: atlas.NewClient(
+			&http.Client{
+				Transport: &digest.Transport{
+					Username: srv.Username,
+					Password: srv.ApiKey,
+				},
+			},
+			srv.URL,
+		),
+		StopContext: context.Background(),
+	}
+
+	grp, err := prvdr.Client.CreateGroup(&atlas.GroupPost{
+		Name:  "test",
+		OrgId: prvdr.OrgId,
+	})
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	groupId = grp.Id
+
+	return
+}
+
+func testResourceConfig(t *testing.T,
+	raw map[string]interface{}) *terraform.ResourceConfig {
+
+	rawConfig, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return terraform.NewResourceConfig(rawConfig)
+}
+
+// testDiff returns the plan of the configuration against the state.
+func testDiff(t *testing.T, rsc *schema.Resource, prvdr *schemas.Provider,
+	state *terraform.InstanceState, raw map[string]interface{}) (
+	*terraform.InstanceDiff, error) {
+
+	return rsc.Diff(state, testResourceConfig(t, raw), prvdr)
+}
+
+// testApply plans and applies the configuration then refreshes the state
+// as Terraform does, a replacement is applied to an empty state.
+func testApply(t *testing.T, rsc *schema.Resource, prvdr *schemas.Provider,
+	state *terraform.InstanceState, raw map[string]interface{}) (
+	*terraform.InstanceState, error) {
+
+	diff, err := testDiff(t, rsc, prvdr, state, raw)
+	if err != nil {
+		return state, err
+	}
+
+	if diff == nil || diff.Empty() {
+		return state, nil
+	}
+
+	if diff.RequiresNew() && state != nil {
+		_, err = rsc.Apply(state, &terraform.InstanceDiff{
+			Destroy: true,
+		}, prvdr)
+		if err != nil {
+			return state, err
+		}
+
+		state = nil
+		diff, err = testDiff(t, rsc, prvdr, state, raw)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	state, err = rsc.Apply(state, diff, prvdr)
+	if err != nil {
+		return state, err
+	}
+
+	return rsc.Refresh(state, prvdr)
+}
@@ -1,18 +1,15 @@
 package resources
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
 	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
 	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
-	"io/ioutil"
-	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -22,6 +19,17 @@ func User() *schema.Resource {
 		Read:   userRead,
 		Update: userUpdate,
 		Delete: userDelete,
+		Importer: &schema.ResourceImporter{
+			State: userImport,
+		},
+		CustomizeDiff: userCustomizeDiff,
+		SchemaVersion: 1,
+		MigrateState:  userMigrateState,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"group_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -38,102 +46,144 @@ func User() *schema.Resource {
 				Required: true,
 			},
 			"database_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"roles"},
+			},
+			"auth_database_name": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Default:  "admin",
+				ForceNew: true,
+			},
+			"roles": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"database_name"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"database_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"collection_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
 			},
 			"password": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"generate_password"},
 			},
-			"mongodb_uri": &schema.Schema{
-				Type:     schema.TypeString,
+			"generate_password": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"password_version": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
+			"adopt_existing": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"mongodb_uri": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 		},
 	}
 }
 
-type userRoleData struct {
-	DatabaseName   string `json:"databaseName"`
-	CollectionName string `json:"collectionName"`
-	RoleName       string `json:"roleName"`
-}
+// userRoles returns the configured roles or the readWrite role on
+// database_name when no roles are configured.
+func userRoles(usr *schemas.User) (roles []atlas.DatabaseUserRole) {
+	if len(usr.Roles) == 0 {
+		roles = []atlas.DatabaseUserRole{
+			atlas.DatabaseUserRole{
+				DatabaseName: usr.DatabaseName,
+				RoleName:     "readWrite",
+			},
+		}
+		return
+	}
 
-type userData struct {
-	DatabaseName string         `json:"databaseName"`
-	Username     string         `json:"username"`
-	GroupId      string         `json:"groupId"`
-	Roles        []userRoleData `json:"roles"`
-}
+	roles = []atlas.DatabaseUserRole{}
+	for _, role := range usr.Roles {
+		roles = append(roles, atlas.DatabaseUserRole{
+			DatabaseName:   role.DatabaseName,
+			CollectionName: role.CollectionName,
+			RoleName:       role.RoleName,
+		})
+	}
 
-type userPostData struct {
-	DatabaseName string         `json:"databaseName"`
-	Username     string         `json:"username"`
-	Password     string         `json:"password"`
-	GroupId      string         `json:"groupId"`
-	Roles        []userRoleData `json:"roles"`
+	return
 }
 
-type userPutData struct {
-	Password string         `json:"password"`
-	Roles    []userRoleData `json:"roles"`
-}
+// userFlattenRoles converts the Atlas roles to the roles format.
+func userFlattenRoles(data *atlas.DatabaseUser) (roles []interface{}) {
+	roles = []interface{}{}
 
-func userGet(prvdr *schemas.Provider, usr *schemas.User) (
-	data *userData, err error) {
-
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/databaseUsers/admin/%s",
-			usr.GroupId,
-			usr.Name,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
-		return
+	for _, role := range data.Roles {
+		roles = append(roles, map[string]interface{}{
+			"role_name":       role.RoleName,
+			"database_name":   role.DatabaseName,
+			"collection_name": role.CollectionName,
+		})
 	}
 
-	req.Header.Set("Accept", "application/json")
+	return
+}
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
-		return
+// userRoleDatabase returns the database of the user when the roles are
+// a single readWrite role on a database.
+func userRoleDatabase(data *atlas.DatabaseUser) string {
+	if len(data.Roles) == 1 && data.Roles[0].RoleName == "readWrite" &&
+		data.Roles[0].CollectionName == "" {
+
+		return data.Roles[0].DatabaseName
 	}
-	defer resp.Body.Close()
+	return ""
+}
 
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+// userSetRoles sets the roles from Atlas when roles are configured,
+// otherwise database_name is set from the single readWrite role.
+func userSetRoles(d *schema.ResourceData, data *atlas.DatabaseUser) {
+	if len(d.Get("roles").([]interface{})) > 0 {
+		d.Set("roles", userFlattenRoles(data))
+	} else {
+		d.Set("database_name", userRoleDatabase(data))
+	}
+}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: User request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+// userValidate checks that the user has database_name or roles and a
+// password or generate_password.
+func userValidate(usr *schemas.User) (err error) {
+	if usr.DatabaseName == "" && len(usr.Roles) == 0 {
+		err = &errortypes.ParseError{
+			errors.Newf("resources: User %s requires database_name "+
+				"or roles", usr.Name),
 		}
 		return
 	}
 
-	data = &userData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
-	if err != nil {
+	if usr.Password == "" && !usr.GeneratePassword {
 		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: User decode failed"),
+			errors.Newf("resources: User %s requires password "+
+				"or generate_password", usr.Name),
 		}
 		return
 	}
@@ -141,202 +191,126 @@ func userGet(prvdr *schemas.Provider, usr *schemas.User) (
 	return
 }
 
-func userPost(prvdr *schemas.Provider, usr *schemas.User) (err error) {
-	data := userPostData{
-		DatabaseName: "admin",
-		Username:     usr.Name,
-		Password:     usr.Password,
-		GroupId:      usr.GroupId,
-		Roles: []userRoleData{
-			userRoleData{
-				DatabaseName: usr.DatabaseName,
-				RoleName:     "readWrite",
-			},
-		},
-	}
+// userGeneratePassword sets a new random password when generate_password
+// is set and the user has no password or a rotation was requested with
+// password_version.
+func userGeneratePassword(d *schema.ResourceData, usr *schemas.User) (
+	err error) {
 
-	body, err := json.Marshal(data)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: User marshal failed"),
-		}
+	if !usr.GeneratePassword {
 		return
 	}
 
-	req, err := http.NewRequest(
-		"POST",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/databaseUsers",
-			usr.GroupId,
-		),
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
+	if usr.Password != "" && !d.HasChange("generate_password") &&
+		!d.HasChange("password_version") {
+
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+	password, err := digest.RandStr(32)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
-
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: User request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
+	usr.Password = password
+	d.Set("password", password)
 
 	return
 }
 
-func userPut(prvdr *schemas.Provider, usr *schemas.User) (
-	data *userData, err error) {
+// userMigrateState sets the arguments added in version 1 on the state of
+// users created by earlier versions, which were always created in the
+// admin database. Without this auth_database_name would replace every user.
+func userMigrateState(version int, state *terraform.InstanceState,
+	m interface{}) (newState *terraform.InstanceState, err error) {
 
-	putData := userPutData{
-		Password: usr.Password,
-		Roles: []userRoleData{
-			userRoleData{
-				DatabaseName: usr.DatabaseName,
-				RoleName:     "readWrite",
-			},
-		},
-	}
-
-	body, err := json.Marshal(putData)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: User marshal failed"),
-		}
+	newState = state
+	if version > 0 || state == nil || state.Attributes == nil {
 		return
 	}
 
-	req, err := http.NewRequest(
-		"PATCH",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/databaseUsers/admin/%s",
-			usr.GroupId,
-			usr.Name,
-		),
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
+	for key, val := range map[string]string{
+		"auth_database_name": "admin",
+		"generate_password":  "false",
+		"adopt_existing":     "false",
+	} {
+		if state.Attributes[key] == "" {
+			state.Attributes[key] = val
 		}
-		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	return
+}
+
+// userCustomizeDiff marks the password and uri as unknown when a new
+// password will be generated so that resources using them are updated in
+// the same apply.
+func userCustomizeDiff(d *schema.ResourceDiff, m interface{}) (
+	err error) {
 
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
+	if d.Id() == "" {
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
+	if d.Get("generate_password").(bool) &&
+		(d.HasChange("generate_password") ||
+			d.HasChange("password_version")) {
+
+		// Clearing the password also clears the keys it prefixes
+		version := d.Get("password_version")
+
+		err = d.SetNewComputed("password")
+		if err != nil {
+			return
 		}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: User request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+		err = d.SetNew("password_version", version)
+		if err != nil {
+			return
 		}
-		return
-	}
 
-	data = &userData{}
-	err = json.NewDecoder(resp.Body).Decode(data)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: User decode failed"),
+		err = d.SetNewComputed("mongodb_uri")
+		if err != nil {
+			return
+		}
+	} else if d.HasChange("password") || d.HasChange("cluster_name") {
+		err = d.SetNewComputed("mongodb_uri")
+		if err != nil {
+			return
 		}
-		return
 	}
 
 	return
 }
 
-func userDel(prvdr *schemas.Provider, usr *schemas.User) (err error) {
-	req, err := http.NewRequest(
-		"DELETE",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/databaseUsers/admin/%s",
-			usr.GroupId,
-			usr.Name,
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
-		return
+func userPost(clnt *atlas.Client, usr *schemas.User) (err error) {
+	postData := &atlas.DatabaseUserPost{
+		DatabaseName: usr.AuthDatabaseName,
+		Username:     usr.Name,
+		Password:     usr.Password,
+		GroupId:      usr.GroupId,
+		Roles:        userRoles(usr),
 	}
 
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+	_, err = clnt.CreateDatabaseUser(usr.GroupId, postData)
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: User request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 && resp.StatusCode != 202 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
+	return
+}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: User request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
+func userPut(clnt *atlas.Client, usr *schemas.User) (
+	data *atlas.DatabaseUser, err error) {
+
+	putData := &atlas.DatabaseUserPut{
+		Password: usr.Password,
+		Roles:    userRoles(usr),
+	}
+
+	data, err = clnt.UpdateDatabaseUser(usr.GroupId, usr.AuthDatabaseName,
+		usr.Name, putData)
+	if err != nil {
 		return
 	}
 
@@ -357,67 +331,185 @@ func userUriParse(usr *schemas.User, inputUri string) (
 	uri.Path = "/" + usr.Name
 	uri.User = url.UserPassword(usr.Name, usr.Password)
 
+	if usr.AuthDatabaseName != "admin" {
+		query := uri.Query()
+		query.Set("authSource", usr.AuthDatabaseName)
+		uri.RawQuery = query.Encode()
+	}
+
 	uriStr = uri.String()
 
 	return
 }
 
-func userCreate(d *schema.ResourceData, m interface{}) (err error) {
+// userImport accepts group_id/username when the group has a single
+// cluster, otherwise group_id/cluster_name/username. The auth database is
+// found from the users of the group. The password cannot be read from
+// Atlas and must be set in the configuration or generated.
+func userImport(d *schema.ResourceData, m interface{}) (
+	data []*schema.ResourceData, err error) {
+
 	prvdr := m.(*schemas.Provider)
-	usr := schemas.LoadUser(d)
+	clnt := prvdr.Client.WithContext(prvdr.StopContext)
 
-	var clstData *clusterData
-	for {
-		clstData, err = clusterGet(prvdr, usr.GroupId, usr.ClusterName)
-		if err != nil {
+	groupId := ""
+	clusterName := ""
+	username := ""
+
+	if strings.Count(d.Id(), "/") >= 2 {
+		parts, e := parseImportId(
+			d.Id(), 3, "group_id/cluster_name/username")
+		if e != nil {
+			err = e
+			return
+		}
+
+		groupId = parts[0]
+		clusterName = parts[1]
+		username = parts[2]
+	} else {
+		parts, e := parseImportId(d.Id(), 2, "group_id/username")
+		if e != nil {
+			err = e
+			return
+		}
+
+		groupId = parts[0]
+		username = parts[1]
+
+		clsts, e := clnt.ListClusters(groupId)
+		if e != nil {
+			err = e
 			return
 		}
 
-		if clstData == nil {
-			err = errortypes.NotFoundError{
-				errors.New("resources: Cluster not found"),
+		if len(clsts) != 1 {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: Group %s has %d clusters, "+
+					"import with group_id/cluster_name/username",
+					groupId, len(clsts)),
 			}
 			return
-		} else if clstData.Available() {
-			break
 		}
 
-		time.Sleep(1 * time.Second)
+		clusterName = clsts[0].Name
 	}
 
-	usrData, err := userGet(prvdr, usr)
+	// The auth database is not part of the id, find the user in any auth
+	// database of the group
+	usrsData, err := clnt.ListDatabaseUsers(groupId)
 	if err != nil {
 		return
 	}
 
-	if usrData != nil {
-		err = userDel(prvdr, usr)
-		if err != nil {
+	var usrData *atlas.DatabaseUser
+	for _, usr := range usrsData {
+		if usr.Username != username {
+			continue
+		}
+
+		if usrData != nil {
+			err = &errortypes.ParseError{
+				errors.Newf("resources: User %s exists in the auth "+
+					"databases %s and %s, it cannot be imported",
+					username, usrData.DatabaseName, usr.DatabaseName),
+			}
 			return
 		}
+		usrData = usr
+	}
+
+	if usrData == nil {
+		err = &errortypes.NotFoundError{
+			errors.Newf("resources: User %s not found", username),
+		}
+		return
+	}
+
+	d.Set("group_id", groupId)
+	d.Set("name", usrData.Username)
+	d.Set("cluster_name", clusterName)
+	d.Set("auth_database_name", usrData.DatabaseName)
+	d.Set("adopt_existing", false)
+
+	// Import the roles when they cannot be described by database_name
+	databaseName := userRoleDatabase(usrData)
+	if databaseName != "" {
+		d.Set("database_name", databaseName)
+	} else {
+		d.Set("roles", userFlattenRoles(usrData))
+	}
+
+	d.SetId(usrData.Username)
+
+	data = []*schema.ResourceData{d}
+
+	return
+}
+
+func userCreate(d *schema.ResourceData, m interface{}) (err error) {
+	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutCreate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
+	usr := schemas.LoadUser(d)
+
+	err = userGeneratePassword(d, usr)
+	if err != nil {
+		return
+	}
+
+	err = userValidate(usr)
+	if err != nil {
+		return
 	}
 
-	err = userPost(prvdr, usr)
+	_, err = clusterWait(ctx, clnt, usr.GroupId, usr.ClusterName)
 	if err != nil {
 		return
 	}
 
-	for {
-		clstData, err = clusterGet(prvdr, usr.GroupId, usr.ClusterName)
+	usrData, err := clnt.GetDatabaseUser(usr.GroupId, usr.AuthDatabaseName,
+		usr.Name)
+	if err != nil {
+		return
+	}
+
+	// Existing users are only updated when adopt_existing is set to avoid
+	// replacing the credentials of a user managed elsewhere
+	if usrData != nil {
+		if !usr.AdoptExisting {
+			err = &errortypes.WriteError{
+				errors.Newf("resources: User %s already exists, import "+
+					"it with the id %s/%s/%s or set adopt_existing",
+					usr.Name, usr.GroupId, usr.ClusterName, usr.Name),
+			}
+			return
+		}
+
+		usrData, err = userPut(clnt, usr)
 		if err != nil {
 			return
 		}
 
-		if clstData == nil {
-			err = errortypes.NotFoundError{
-				errors.New("resources: Cluster not found"),
+		if usrData == nil {
+			err = &errortypes.NotFoundError{
+				errors.Newf("resources: User %s not found", usr.Name),
 			}
 			return
-		} else if clstData.Available() {
-			break
 		}
+	} else {
+		err = userPost(clnt, usr)
+		if err != nil {
+			return
+		}
+	}
+
+	d.SetId(usr.Name)
 
-		time.Sleep(1 * time.Second)
+	clstData, err := clusterWait(ctx, clnt, usr.GroupId, usr.ClusterName)
+	if err != nil {
+		return
 	}
 
 	uri, err := userUriParse(usr, clstData.MongoUriWithOptions)
@@ -426,16 +518,18 @@ func userCreate(d *schema.ResourceData, m interface{}) (err error) {
 	}
 
 	d.Set("mongodb_uri", uri)
-	d.SetId(usr.Name)
 
 	return
 }
 
 func userRead(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutRead)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	usr := schemas.LoadUser(d)
 
-	clstData, err := clusterGet(prvdr, usr.GroupId, usr.ClusterName)
+	clstData, err := clnt.GetCluster(usr.GroupId, usr.ClusterName)
 	if err != nil {
 		return
 	}
@@ -447,7 +541,8 @@ func userRead(d *schema.ResourceData, m interface{}) (err error) {
 		return
 	}
 
-	usrData, err := userGet(prvdr, usr)
+	usrData, err := clnt.GetDatabaseUser(usr.GroupId, usr.AuthDatabaseName,
+		usr.Name)
 	if err != nil {
 		return
 	}
@@ -457,6 +552,8 @@ func userRead(d *schema.ResourceData, m interface{}) (err error) {
 		return
 	}
 
+	userSetRoles(d, usrData)
+
 	uri, err := userUriParse(usr, clstData.MongoUriWithOptions)
 	if err != nil {
 		return
@@ -470,28 +567,27 @@ func userRead(d *schema.ResourceData, m interface{}) (err error) {
 
 func userUpdate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutUpdate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	usr := schemas.LoadUser(d)
 
-	var clstData *clusterData
-	for {
-		clstData, err = clusterGet(prvdr, usr.GroupId, usr.ClusterName)
-		if err != nil {
-			return
-		}
+	err = userGeneratePassword(d, usr)
+	if err != nil {
+		return
+	}
 
-		if clstData == nil {
-			err = errortypes.NotFoundError{
-				errors.New("resources: Cluster not found"),
-			}
-			return
-		} else if clstData.Available() {
-			break
-		}
+	err = userValidate(usr)
+	if err != nil {
+		return
+	}
 
-		time.Sleep(1 * time.Second)
+	_, err = clusterWait(ctx, clnt, usr.GroupId, usr.ClusterName)
+	if err != nil {
+		return
 	}
 
-	usrData, err := userPut(prvdr, usr)
+	usrData, err := userPut(clnt, usr)
 	if err != nil {
 		return
 	}
@@ -501,22 +597,9 @@ func userUpdate(d *schema.ResourceData, m interface{}) (err error) {
 		return
 	}
 
-	for {
-		clstData, err = clusterGet(prvdr, usr.GroupId, usr.ClusterName)
-		if err != nil {
-			return
-		}
-
-		if clstData == nil {
-			err = errortypes.NotFoundError{
-				errors.New("resources: Cluster not found"),
-			}
-			return
-		} else if clstData.Available() {
-			break
-		}
-
-		time.Sleep(1 * time.Second)
+	clstData, err := clusterWait(ctx, clnt, usr.GroupId, usr.ClusterName)
+	if err != nil {
+		return
 	}
 
 	uri, err := userUriParse(usr, clstData.MongoUriWithOptions)
@@ -532,9 +615,13 @@ func userUpdate(d *schema.ResourceData, m interface{}) (err error) {
 
 func userDelete(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutDelete)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	usr := schemas.LoadUser(d)
 
-	err = userDel(prvdr, usr)
+	err = clnt.DeleteDatabaseUser(usr.GroupId, usr.AuthDatabaseName,
+		usr.Name)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,203 @@
+package resources
+
+import (
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"testing"
+)
+
+func TestUserImport(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	_, err := testApply(t, Cluster(), prvdr, nil, map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, authDatabase := range []string{"admin", "users"} {
+		_, err = prvdr.Client.CreateDatabaseUser(groupId,
+			&atlas.DatabaseUserPost{
+				DatabaseName: authDatabase,
+				Username:     authDatabase + "-user",
+				Password:     "password",
+				GroupId:      groupId,
+				Roles: []atlas.DatabaseUserRole{
+					atlas.DatabaseUserRole{
+						DatabaseName: "test",
+						RoleName:     "readWrite",
+					},
+				},
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		id           string
+		authDatabase string
+	}{
+		{groupId + "/admin-user", "admin"},
+		{groupId + "/test/users-user", "users"},
+	} {
+		d := User().Data(nil)
+		d.SetId(test.id)
+
+		data, err := User().Importer.State(d, prvdr)
+		if err != nil {
+			t.Errorf("%s import failed %s", test.id, err)
+			continue
+		}
+
+		d = data[0]
+		if d.Get("auth_database_name") != test.authDatabase ||
+			d.Get("database_name") != "test" ||
+			d.Get("cluster_name") != "test" {
+
+			t.Errorf("%s imported auth database %s database %s "+
+				"cluster %s", test.id, d.Get("auth_database_name"),
+				d.Get("database_name"), d.Get("cluster_name"))
+		}
+	}
+}
+
+func TestUserRotatePassword(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	_, err := testApply(t, Cluster(), prvdr, nil, map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := map[string]interface{}{
+		"group_id":          groupId,
+		"name":              "test",
+		"cluster_name":      "test",
+		"database_name":     "test",
+		"generate_password": true,
+	}
+
+	state, err := testApply(t, User(), prvdr, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	password := state.Attributes["password"]
+	uri := state.Attributes["mongodb_uri"]
+	if password == "" || uri == "" {
+		t.Fatal("password or mongodb_uri not set")
+	}
+
+	raw["password_version"] = 1
+
+	diff, err := testDiff(t, User(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"password", "mongodb_uri"} {
+		attr := diff.Attributes[key]
+		if attr == nil || !attr.NewComputed {
+			t.Errorf("%s not computed in rotation plan", key)
+		}
+	}
+
+	state, err = testApply(t, User(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["password"] == password ||
+		state.Attributes["mongodb_uri"] == uri {
+
+		t.Error("password or mongodb_uri not rotated")
+	}
+
+	diff, err = testDiff(t, User(), prvdr, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && !diff.Empty() {
+		t.Errorf("plan not empty %#v", diff.Attributes)
+	}
+}
+
+func TestUserMigrateState(t *testing.T) {
+	prvdr, srv, groupId := testProvider(t)
+	defer srv.Close()
+
+	_, err := testApply(t, Cluster(), prvdr, nil, map[string]interface{}{
+		"group_id": groupId,
+		"name":     "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = prvdr.Client.CreateDatabaseUser(groupId,
+		&atlas.DatabaseUserPost{
+			DatabaseName: "admin",
+			Username:     "test",
+			Password:     "password",
+			GroupId:      groupId,
+			Roles: []atlas.DatabaseUserRole{
+				atlas.DatabaseUserRole{
+					DatabaseName: "test",
+					RoleName:     "readWrite",
+				},
+			},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// State of a user created before auth_database_name was added
+	state := &terraform.InstanceState{
+		ID: "test",
+		Attributes: map[string]string{
+			"id":            "test",
+			"group_id":      groupId,
+			"name":          "test",
+			"cluster_name":  "test",
+			"database_name": "test",
+			"password":      "password",
+		},
+	}
+
+	state, err = User().Refresh(state, prvdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state == nil {
+		t.Fatal("user removed from state")
+	}
+	if state.Attributes["auth_database_name"] != "admin" {
+		t.Errorf("auth_database_name %q, expected admin",
+			state.Attributes["auth_database_name"])
+	}
+
+	diff, err := testDiff(t, User(), prvdr, state, map[string]interface{}{
+		"group_id":      groupId,
+		"name":          "test",
+		"cluster_name":  "test",
+		"database_name": "test",
+		"password":      "password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && !diff.Empty() {
+		t.Errorf("plan not empty %#v", diff.Attributes)
+	}
+}
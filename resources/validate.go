@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"strings"
+)
+
+// validateStringIn returns a validate func that accepts one of the values
+// ignoring case.
+func validateStringIn(values ...string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (warns []string, errs []error) {
+		val := v.(string)
+
+		for _, value := range values {
+			if strings.EqualFold(val, value) {
+				return
+			}
+		}
+
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %s",
+			k, strings.Join(values, ", "), val))
+
+		return
+	}
+}
+
+// validateIntIn returns a validate func that accepts one of the values.
+func validateIntIn(values ...int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (warns []string, errs []error) {
+		val := v.(int)
+
+		strs := []string{}
+		for _, value := range values {
+			if val == value {
+				return
+			}
+			strs = append(strs, fmt.Sprintf("%d", value))
+		}
+
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %d",
+			k, strings.Join(strs, ", "), val))
+
+		return
+	}
+}
+
+// validateIntBetween returns a validate func that accepts values from min
+// to max inclusive.
+func validateIntBetween(min, max int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (warns []string, errs []error) {
+		val := v.(int)
+
+		if val < min || val > max {
+			errs = append(errs, fmt.Errorf(
+				"%s must be between %d and %d, got %d", k, min, max, val))
+		}
+
+		return
+	}
+}
+
+// validateSize accepts the instance sizes available on any provider.
+func validateSize(v interface{}, k string) (warns []string, errs []error) {
+	size := strings.ToUpper(v.(string))
+
+	if clusterSharedSizes[size] {
+		return
+	}
+
+	for _, sizes := range providerSizes {
+		if sizes[size] {
+			return
+		}
+	}
+
+	errs = append(errs, fmt.Errorf(
+		"%s %s is not an Atlas instance size such as M10", k, v))
+
+	return
+}
+
+// validateRegion accepts the regions available on any provider, the
+// region is checked against the provider in the cluster CustomizeDiff.
+func validateRegion(v interface{}, k string) (
+	warns []string, errs []error) {
+
+	for provider := range providerRegions {
+		if _, ok := regionAtlas(provider, v.(string)); ok {
+			return
+		}
+	}
+
+	errs = append(errs, fmt.Errorf(
+		"%s %s is not an Atlas region such as us-east-2", k, v))
+
+	return
+}
@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"testing"
+)
+
+func TestValidateRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		valid  bool
+	}{
+		{"us-east-1", true},
+		{"US_EAST_1", true},
+		{"us-central1", true},
+		{"eastus2", true},
+		{"mars-1", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		_, errs := validateRegion(test.region, "region")
+		if (len(errs) == 0) != test.valid {
+			t.Errorf("%q valid %t, expected %t",
+				test.region, len(errs) == 0, test.valid)
+		}
+	}
+}
+
+func TestValidateSize(t *testing.T) {
+	tests := []struct {
+		size  string
+		valid bool
+	}{
+		{"M10", true},
+		{"m10", true},
+		{"M2", true},
+		{"M0", true},
+		{"R40", true},
+		{"M1", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		_, errs := validateSize(test.size, "size")
+		if (len(errs) == 0) != test.valid {
+			t.Errorf("%q valid %t, expected %t",
+				test.size, len(errs) == 0, test.valid)
+		}
+	}
+}
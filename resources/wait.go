@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"context"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
+	"time"
+)
+
+const (
+	waitMinInterval = 1 * time.Second
+	waitMaxInterval = 10 * time.Second
+)
+
+// refreshFunc returns the current state of a resource and whether the
+// wait is complete.
+type refreshFunc func() (state string, done bool, err error)
+
+// operationContext returns a context for a resource operation that is done
+// when the operation timeout is reached or Terraform is interrupted.
+func operationContext(prvdr *schemas.Provider, d *schema.ResourceData,
+	timeoutKey string) (context.Context, context.CancelFunc) {
+
+	return context.WithTimeout(prvdr.StopContext, d.Timeout(timeoutKey))
+}
+
+// waitFor polls refresh with increasing intervals until it reports done,
+// returns an error or ctx is done. The last observed state is included in
+// the error when the wait is abandoned.
+func waitFor(ctx context.Context, name string, refresh refreshFunc) (
+	err error) {
+
+	interval := waitMinInterval
+	state := ""
+
+	for {
+		cur, done, e := refresh()
+		if e != nil {
+			err = e
+			if ctx.Err() != nil {
+				err = waitError(ctx, name, state)
+			}
+			return
+		}
+		state = cur
+
+		if done {
+			return
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = waitError(ctx, name, state)
+			return
+		case <-timer.C:
+		}
+
+		interval = interval * 3 / 2
+		if interval > waitMaxInterval {
+			interval = waitMaxInterval
+		}
+	}
+}
+
+func waitError(ctx context.Context, name, state string) error {
+	if state == "" {
+		state = "unknown"
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return &errortypes.TimeoutError{
+			errors.Newf("resources: Timeout waiting for %s, last state %s",
+				name, state),
+		}
+	}
+
+	return &errortypes.RequestError{
+		errors.Newf("resources: Cancelled waiting for %s, last state %s",
+			name, state),
+	}
+}
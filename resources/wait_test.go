@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWaitForLastState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reads := 0
+	err := waitFor(ctx, "cluster test", func() (string, bool, error) {
+		reads += 1
+		if reads == 1 {
+			return "CREATING", false, nil
+		}
+
+		// Interrupted while the request is in flight
+		cancel()
+		return "", false, errors.New("request cancelled")
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "last state CREATING") {
+		t.Errorf("wait error %v, expected last state CREATING", err)
+	}
+}
+
+func TestWaitForError(t *testing.T) {
+	err := waitFor(context.Background(), "cluster test",
+		func() (string, bool, error) {
+			return "", false, errors.New("refresh failed")
+		})
+
+	if err == nil || err.Error() != "refresh failed" {
+		t.Errorf("wait error %v, expected refresh failed", err)
+	}
+}
@@ -1,18 +1,12 @@
 package resources
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/pritunl/terraform-provider-mongodbatlas/constants"
-	"github.com/pritunl/terraform-provider-mongodbatlas/digest"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
 	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
 	"github.com/pritunl/terraform-provider-mongodbatlas/schemas"
-	"io/ioutil"
-	"net/http"
-	"net/url"
+	"time"
 )
 
 func Whitelist() *schema.Resource {
@@ -20,6 +14,13 @@ func Whitelist() *schema.Resource {
 		Create: whitelistCreate,
 		Read:   whitelistRead,
 		Delete: whitelistDelete,
+		Importer: &schema.ResourceImporter{
+			State: whitelistImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"group_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -35,185 +36,50 @@ func Whitelist() *schema.Resource {
 	}
 }
 
-type whitelistPostData struct {
-	CidrBlock string `json:"cidrBlock"`
-	Comment   string `json:"comment"`
-}
-
-func whitelistGet(prvdr *schemas.Provider, wl *schemas.Whitelist) (
-	exists bool, err error) {
-
-	req, err := http.NewRequest(
-		"GET",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/whitelist/%s",
-			wl.GroupId,
-			url.QueryEscape(wl.Address),
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
-		return
-	}
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 404 {
-		exists = false
-		return
-	} else if resp.StatusCode != 200 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
-
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Peer request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
-
-	exists = true
-
-	return
-}
-
-func whitelistPost(prvdr *schemas.Provider, wl *schemas.Whitelist) (
-	err error) {
+func whitelistImport(d *schema.ResourceData, m interface{}) (
+	data []*schema.ResourceData, err error) {
 
-	postData := []*whitelistPostData{}
-	postData = append(postData, &whitelistPostData{
-		CidrBlock: wl.Address,
-	})
-
-	body, err := json.Marshal(postData)
-	if err != nil {
-		err = &errortypes.ParseError{
-			errors.Wrap(err, "resources: Whitelist marshal failed"),
-		}
-		return
-	}
+	prvdr := m.(*schemas.Provider)
+	clnt := prvdr.Client.WithContext(prvdr.StopContext)
 
-	req, err := http.NewRequest(
-		"POST",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/whitelist",
-			wl.GroupId,
-		),
-		bytes.NewBuffer(body),
-	)
+	parts, err := parseImportId(d.Id(), 2, "group_id/address")
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
 		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
+	wlData, err := clnt.GetWhitelistEntry(parts[0], parts[1])
 	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 201 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
 
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Whitelist request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
+	if wlData == nil {
+		err = &errortypes.NotFoundError{
+			errors.Newf("resources: Whitelist %s not found", parts[1]),
 		}
 		return
 	}
 
-	return
-}
+	d.Set("group_id", parts[0])
+	d.Set("address", parts[1])
+	d.SetId(parts[1])
 
-func whitelistDel(prvdr *schemas.Provider, wl *schemas.Whitelist) (
-	err error) {
-
-	req, err := http.NewRequest(
-		"DELETE",
-		constants.BaseUrl+fmt.Sprintf(
-			"/api/atlas/v1.0/groups/%s/whitelist/%s",
-			wl.GroupId,
-			url.QueryEscape(wl.Address),
-		),
-		nil,
-	)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
-		return
-	}
-
-	resp, err := digest.Do(client, req, prvdr.Username, prvdr.ApiKey)
-	if err != nil {
-		err = &errortypes.RequestError{
-			errors.Wrap(err, "resources: Whitelist request failed"),
-		}
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 404 {
-		return
-	} else if resp.StatusCode != 200 && resp.StatusCode != 202 {
-		respBodyStr := ""
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		if respBody != nil {
-			respBodyStr = string(respBody)
-		}
-
-		err = &errortypes.RequestError{
-			errors.Wrapf(
-				err,
-				"resources: Whitelist request bad status %d %s",
-				resp.StatusCode,
-				respBodyStr,
-			),
-		}
-		return
-	}
+	data = []*schema.ResourceData{d}
 
 	return
 }
 
 func whitelistCreate(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutCreate)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	wl := schemas.LoadWhitelist(d)
 
-	err = whitelistPost(prvdr, wl)
+	err = clnt.CreateWhitelistEntries(wl.GroupId, []*atlas.WhitelistEntry{
+		&atlas.WhitelistEntry{
+			CidrBlock: wl.Address,
+		},
+	})
 	if err != nil {
 		return
 	}
@@ -225,14 +91,17 @@ func whitelistCreate(d *schema.ResourceData, m interface{}) (err error) {
 
 func whitelistRead(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutRead)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	wl := schemas.LoadWhitelist(d)
 
-	exists, err := whitelistGet(prvdr, wl)
+	wlData, err := clnt.GetWhitelistEntry(wl.GroupId, wl.Address)
 	if err != nil {
 		return
 	}
 
-	if exists {
+	if wlData != nil {
 		d.SetId(wl.Address)
 	} else {
 		d.SetId("")
@@ -243,9 +112,12 @@ func whitelistRead(d *schema.ResourceData, m interface{}) (err error) {
 
 func whitelistDelete(d *schema.ResourceData, m interface{}) (err error) {
 	prvdr := m.(*schemas.Provider)
+	ctx, cancel := operationContext(prvdr, d, schema.TimeoutDelete)
+	defer cancel()
+	clnt := prvdr.Client.WithContext(ctx)
 	wl := schemas.LoadWhitelist(d)
 
-	err = whitelistDel(prvdr, wl)
+	err = clnt.DeleteWhitelistEntry(wl.GroupId, wl.Address)
 	if err != nil {
 		return
 	}
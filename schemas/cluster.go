@@ -1,22 +1,124 @@
 package schemas
 
-import (
-	"github.com/hashicorp/terraform/helper/schema"
-)
+// clusterGetter is implemented by schema.ResourceData and
+// schema.ResourceDiff to load a cluster from the state or a plan.
+type clusterGetter interface {
+	Id() string
+	Get(key string) interface{}
+	GetOkExists(key string) (interface{}, bool)
+}
+
+type ClusterRegion struct {
+	RegionName     string
+	ElectableNodes int
+	Priority       int
+	ReadOnlyNodes  int
+	AnalyticsNodes int
+}
+
+type ClusterReplicationSpec struct {
+	Id        string
+	ZoneName  string
+	NumShards int
+	Regions   []*ClusterRegion
+}
+
+type ClusterAdvancedConfig struct {
+	FailIndexKeyTooLong       bool
+	JavascriptEnabled         bool
+	MinimumEnabledTlsProtocol string
+	NoTableScan               bool
+	OplogSizeMb               int
+	DefaultReadConcern        string
+	DefaultWriteConcern       string
+}
 
 type Cluster struct {
-	Id                string
-	GroupId           string
-	Name              string
-	ServiceProvider   string
-	Region            string
-	Size              string
-	DiskSizeGb        int
-	ReplicationFactor int
-	MongoDbVersion    string
-}
-
-func LoadCluster(d *schema.ResourceData) (sch *Cluster) {
+	Id                    string
+	GroupId               string
+	Name                  string
+	ServiceProvider       string
+	Region                string
+	Size                  string
+	DiskIops              int
+	VolumeType            string
+	EncryptEbsVolume      *bool
+	DiskTypeName          string
+	ClusterType           string
+	NumShards             int
+	DiskSizeGb            int
+	ReplicationFactor     int
+	ReplicationSpecs      []*ClusterReplicationSpec
+	MongoDbVersion        string
+	BackupEnabled         bool
+	ProviderBackup        bool
+	AutoScalingDiskGb     bool
+	AutoScalingCompute    bool
+	AutoScalingScaleDown  bool
+	AutoScalingMinSize    string
+	AutoScalingMaxSize    string
+	Paused                bool
+	AdvancedConfig        *ClusterAdvancedConfig
+	TerminationProtection bool
+	AdoptExisting         bool
+}
+
+func loadClusterReplicationSpecs(d clusterGetter) (
+	specs []*ClusterReplicationSpec) {
+
+	specs = []*ClusterReplicationSpec{}
+
+	for _, specInf := range d.Get("replication_spec").([]interface{}) {
+		specData := specInf.(map[string]interface{})
+
+		spec := &ClusterReplicationSpec{
+			Id:        specData["id"].(string),
+			ZoneName:  specData["zone_name"].(string),
+			NumShards: specData["num_shards"].(int),
+			Regions:   []*ClusterRegion{},
+		}
+
+		for _, regionInf := range specData["regions_config"].([]interface{}) {
+			regionData := regionInf.(map[string]interface{})
+
+			spec.Regions = append(spec.Regions, &ClusterRegion{
+				RegionName:     regionData["region_name"].(string),
+				ElectableNodes: regionData["electable_nodes"].(int),
+				Priority:       regionData["priority"].(int),
+				ReadOnlyNodes:  regionData["read_only_nodes"].(int),
+				AnalyticsNodes: regionData["analytics_nodes"].(int),
+			})
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return
+}
+
+func loadClusterAdvancedConfig(d clusterGetter) (
+	config *ClusterAdvancedConfig) {
+
+	configs := d.Get("advanced_configuration").([]interface{})
+	if len(configs) == 0 || configs[0] == nil {
+		return
+	}
+	conf := configs[0].(map[string]interface{})
+
+	config = &ClusterAdvancedConfig{
+		FailIndexKeyTooLong:       conf["fail_index_key_too_long"].(bool),
+		JavascriptEnabled:         conf["javascript_enabled"].(bool),
+		MinimumEnabledTlsProtocol: conf["minimum_enabled_tls_protocol"].(string),
+		NoTableScan:               conf["no_table_scan"].(bool),
+		OplogSizeMb:               conf["oplog_size_mb"].(int),
+		DefaultReadConcern:        conf["default_read_concern"].(string),
+		DefaultWriteConcern:       conf["default_write_concern"].(string),
+	}
+
+	return
+}
+
+func LoadCluster(d clusterGetter) (sch *Cluster) {
 	sch = &Cluster{
 		Id:                d.Id(),
 		GroupId:           d.Get("group_id").(string),
@@ -24,9 +126,36 @@ func LoadCluster(d *schema.ResourceData) (sch *Cluster) {
 		ServiceProvider:   d.Get("service_provider").(string),
 		Region:            d.Get("region").(string),
 		Size:              d.Get("size").(string),
+		DiskIops:          d.Get("disk_iops").(int),
+		VolumeType:        d.Get("volume_type").(string),
+		DiskTypeName:      d.Get("disk_type_name").(string),
+		ClusterType:       d.Get("cluster_type").(string),
+		NumShards:         d.Get("num_shards").(int),
 		DiskSizeGb:        d.Get("disk_size_gb").(int),
 		ReplicationFactor: d.Get("replication_factor").(int),
+		ReplicationSpecs:  loadClusterReplicationSpecs(d),
 		MongoDbVersion:    d.Get("mongodb_version").(string),
+		Paused:            d.Get("paused").(bool),
+		AdvancedConfig:    loadClusterAdvancedConfig(d),
+		BackupEnabled:     d.Get("backup_enabled").(bool),
+		ProviderBackup:    d.Get("provider_backup_enabled").(bool),
+		AutoScalingDiskGb: d.Get("auto_scaling_disk_gb_enabled").(bool),
+		AutoScalingCompute: d.Get(
+			"auto_scaling_compute_enabled").(bool),
+		AutoScalingScaleDown: d.Get(
+			"auto_scaling_compute_scale_down_enabled").(bool),
+		AutoScalingMinSize: d.Get(
+			"auto_scaling_min_instance_size").(string),
+		AutoScalingMaxSize: d.Get(
+			"auto_scaling_max_instance_size").(string),
+		TerminationProtection: d.Get(
+			"termination_protection_enabled").(bool),
+		AdoptExisting: d.Get("adopt_existing").(bool),
+	}
+
+	if encrypt, ok := d.GetOkExists("encrypt_ebs_volume"); ok {
+		encryptBool := encrypt.(bool)
+		sch.EncryptEbsVolume = &encryptBool
 	}
 
 	return
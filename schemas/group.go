@@ -5,14 +5,18 @@ import (
 )
 
 type Group struct {
-	Id   string
-	Name string
+	Id            string
+	Name          string
+	ForceDestroy  bool
+	AdoptExisting bool
 }
 
 func LoadGroup(d *schema.ResourceData) (sch *Group) {
 	sch = &Group{
-		Id:   d.Id(),
-		Name: d.Get("name").(string),
+		Id:            d.Id(),
+		Name:          d.Get("name").(string),
+		ForceDestroy:  d.Get("force_destroy").(bool),
+		AdoptExisting: d.Get("adopt_existing").(bool),
 	}
 
 	return
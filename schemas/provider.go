@@ -1,20 +1,98 @@
 package schemas
 
 import (
+	"context"
+	"github.com/dropbox/godropbox/errors"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pritunl/terraform-provider-mongodbatlas/atlas"
+	"github.com/pritunl/terraform-provider-mongodbatlas/errortypes"
+	"os"
+	"strings"
 )
 
 type Provider struct {
-	Username string
-	ApiKey   string
-	OrgId    string
+	Username          string
+	ApiKey            string
+	PublicKey         string
+	PrivateKey        string
+	OrgId             string
+	BaseUrl           string
+	MaxRetries        int
+	RetryMaxWait      int
+	RequestsPerMinute int
+	Client            *atlas.Client
+	StopContext       context.Context
 }
 
-func LoadProvider(d *schema.ResourceData) (sch *Provider) {
+// Credentials returns the digest username and password, either the
+// programmatic API key pair or the legacy username and personal API key.
+func (p *Provider) Credentials() (username, password string) {
+	if p.PublicKey != "" {
+		return p.PublicKey, p.PrivateKey
+	}
+	return p.Username, p.ApiKey
+}
+
+func LoadProvider(d *schema.ResourceData) (sch *Provider, err error) {
 	sch = &Provider{
-		Username: d.Get("username").(string),
-		ApiKey:   d.Get("api_key").(string),
-		OrgId:    d.Get("org_id").(string),
+		Username:   d.Get("username").(string),
+		ApiKey:     d.Get("api_key").(string),
+		PublicKey:  d.Get("public_key").(string),
+		PrivateKey: d.Get("private_key").(string),
+		OrgId:      d.Get("org_id").(string),
+		BaseUrl: strings.TrimRight(
+			d.Get("base_url").(string), "/"),
+		MaxRetries:        d.Get("max_retries").(int),
+		RetryMaxWait:      d.Get("retry_max_wait").(int),
+		RequestsPerMinute: d.Get("requests_per_minute").(int),
+	}
+
+	legacy := sch.Username != "" || sch.ApiKey != ""
+	programmatic := sch.PublicKey != "" || sch.PrivateKey != ""
+
+	if legacy && programmatic {
+		err = &errortypes.ParseError{
+			errors.New("schemas: Provider username and api_key cannot " +
+				"be used with public_key and private_key"),
+		}
+		return
+	}
+
+	// Environment credentials only fill the method set in the
+	// configuration, the programmatic key is preferred when the
+	// configuration has no credentials
+	if !legacy {
+		if sch.PublicKey == "" {
+			sch.PublicKey = os.Getenv("MONGODB_ATLAS_PUBLIC_KEY")
+		}
+		if sch.PrivateKey == "" {
+			sch.PrivateKey = os.Getenv("MONGODB_ATLAS_PRIVATE_KEY")
+		}
+		programmatic = sch.PublicKey != "" || sch.PrivateKey != ""
+	}
+	if !programmatic {
+		if sch.Username == "" {
+			sch.Username = os.Getenv("MONGODB_ATLAS_USERNAME")
+		}
+		if sch.ApiKey == "" {
+			sch.ApiKey = os.Getenv("MONGODB_ATLAS_API_KEY")
+		}
+	}
+
+	if programmatic {
+		if sch.PublicKey == "" || sch.PrivateKey == "" {
+			err = &errortypes.ParseError{
+				errors.New("schemas: Provider public_key and " +
+					"private_key must both be set"),
+			}
+			return
+		}
+	} else if sch.Username == "" || sch.ApiKey == "" {
+		err = &errortypes.ParseError{
+			errors.New("schemas: Provider requires either public_key " +
+				"and private_key or username and api_key"),
+		}
+		return
 	}
 
 	return
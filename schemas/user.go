@@ -4,25 +4,51 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+type UserRole struct {
+	RoleName       string
+	DatabaseName   string
+	CollectionName string
+}
+
 type User struct {
-	Id           string
-	GroupId      string
-	Name         string
-	ClusterName  string
-	DatabaseName string
-	Password     string
-	MongoDbUri   string
+	Id               string
+	GroupId          string
+	Name             string
+	ClusterName      string
+	DatabaseName     string
+	AuthDatabaseName string
+	Roles            []*UserRole
+	Password         string
+	GeneratePassword bool
+	PasswordVersion  int
+	AdoptExisting    bool
+	MongoDbUri       string
 }
 
 func LoadUser(d *schema.ResourceData) (sch *User) {
 	sch = &User{
-		Id:           d.Id(),
-		GroupId:      d.Get("group_id").(string),
-		Name:         d.Get("name").(string),
-		ClusterName:  d.Get("cluster_name").(string),
-		DatabaseName: d.Get("database_name").(string),
-		Password:     d.Get("password").(string),
-		MongoDbUri:   d.Get("mongodb_uri").(string),
+		Id:               d.Id(),
+		GroupId:          d.Get("group_id").(string),
+		Name:             d.Get("name").(string),
+		ClusterName:      d.Get("cluster_name").(string),
+		DatabaseName:     d.Get("database_name").(string),
+		AuthDatabaseName: d.Get("auth_database_name").(string),
+		Roles:            []*UserRole{},
+		Password:         d.Get("password").(string),
+		GeneratePassword: d.Get("generate_password").(bool),
+		PasswordVersion:  d.Get("password_version").(int),
+		AdoptExisting:    d.Get("adopt_existing").(bool),
+		MongoDbUri:       d.Get("mongodb_uri").(string),
+	}
+
+	for _, roleInf := range d.Get("roles").([]interface{}) {
+		roleData := roleInf.(map[string]interface{})
+
+		sch.Roles = append(sch.Roles, &UserRole{
+			RoleName:       roleData["role_name"].(string),
+			DatabaseName:   roleData["database_name"].(string),
+			CollectionName: roleData["collection_name"].(string),
+		})
 	}
 
 	return
@@ -0,0 +1,33 @@
+package transport
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Logging is an http.RoundTripper that logs each request and response
+// status at debug level, visible with TF_LOG=DEBUG. Headers and bodies are
+// not logged to avoid leaking credentials.
+type Logging struct {
+	Transport http.RoundTripper
+}
+
+func (t *Logging) RoundTrip(req *http.Request) (
+	resp *http.Response, err error) {
+
+	start := time.Now()
+
+	resp, err = t.Transport.RoundTrip(req)
+	if err != nil {
+		log.Printf("[DEBUG] atlas: %s %s error %s",
+			req.Method, req.URL.String(), err)
+		return
+	}
+
+	log.Printf("[DEBUG] atlas: %s %s status %d in %s",
+		req.Method, req.URL.String(), resp.StatusCode,
+		time.Since(start))
+
+	return
+}
@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket allowing a steady rate of requests per minute
+// with short bursts. A single limiter is shared by all resources using the
+// same provider configuration.
+type Limiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(perMinute, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:   float64(perMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *Limiter) Wait(ctx context.Context) (err error) {
+	l.lock.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= 1
+	tokens := l.tokens
+	l.lock.Unlock()
+
+	if tokens >= 0 {
+		return
+	}
+
+	wait := time.Duration(-tokens / l.rate * float64(time.Second))
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.lock.Lock()
+		l.tokens += 1
+		l.lock.Unlock()
+		err = ctx.Err()
+	case <-timer.C:
+	}
+
+	return
+}
+
+// RateLimit is an http.RoundTripper that waits on a shared Limiter before
+// each round trip.
+type RateLimit struct {
+	Transport http.RoundTripper
+	Limiter   *Limiter
+}
+
+func (t *RateLimit) RoundTrip(req *http.Request) (
+	resp *http.Response, err error) {
+
+	err = t.Limiter.Wait(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return
+	}
+
+	resp, err = t.Transport.RoundTrip(req)
+
+	return
+}
@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterBurst(t *testing.T) {
+	lmtr := NewLimiter(600, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err := lmtr.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("burst waited %s, expected no wait", time.Since(start))
+	}
+
+	// Tokens are added at 10 per second once the burst is used
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		err := lmtr.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if time.Since(start) < 150*time.Millisecond {
+		t.Errorf("waited %s, expected 200ms", time.Since(start))
+	}
+}
+
+func TestLimiterMinimumBurst(t *testing.T) {
+	lmtr := NewLimiter(60, 0)
+
+	if lmtr.burst != 1 || lmtr.tokens != 1 {
+		t.Errorf("burst %.0f tokens %.0f, expected 1 1",
+			lmtr.burst, lmtr.tokens)
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	lmtr := NewLimiter(1, 1)
+
+	err := lmtr.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = lmtr.Wait(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("wait error %v, expected deadline exceeded", err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Errorf("wait returned after %s", time.Since(start))
+	}
+
+	// The token of a canceled wait is returned to the bucket
+	if lmtr.tokens < -0.5 {
+		t.Errorf("tokens %.2f, expected 0", lmtr.tokens)
+	}
+}
+
+func TestRateLimitCancel(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+		},
+	))
+	defer srv.Close()
+
+	clnt := &http.Client{
+		Transport: &RateLimit{
+			Transport: http.DefaultTransport,
+			Limiter:   NewLimiter(1, 1),
+		},
+	}
+
+	resp, err := clnt.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequest("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = clnt.Do(req.WithContext(ctx))
+	if err == nil {
+		t.Error("request expected context error")
+	}
+
+	if count != 1 {
+		t.Errorf("sent %d requests, expected 1", count)
+	}
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry is an http.RoundTripper that retries transient failures with
+// exponential backoff and jitter. Rate limited responses are retried for
+// any method, server errors and network errors are only retried for
+// idempotent methods so that a POST is never sent twice after it may have
+// been processed.
+type Retry struct {
+	Transport  http.RoundTripper
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Retry) retryable(req *http.Request, resp *http.Response,
+	err error) bool {
+
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+
+		if !idempotent(req.Method) {
+			return false
+		}
+
+		if _, ok := err.(net.Error); ok {
+			return true
+		}
+
+		return false
+	}
+
+	switch resp.StatusCode {
+	case 429:
+		return true
+	case 500, 502, 503, 504:
+		return idempotent(req.Method)
+	default:
+		return false
+	}
+}
+
+// backoff returns the wait before the next attempt, using the Retry-After
+// header when present and otherwise exponential backoff with jitter.
+func (t *Retry) backoff(attempt int, resp *http.Response) (
+	wait time.Duration) {
+
+	if resp != nil {
+		wait = retryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	if wait <= 0 {
+		wait = t.MinWait << uint(attempt)
+		if wait <= 0 || wait > t.MaxWait {
+			wait = t.MaxWait
+		}
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+
+	if wait > t.MaxWait {
+		wait = t.MaxWait
+	}
+
+	return
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	secs, err := strconv.Atoi(header)
+	if err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	date, err := http.ParseTime(header)
+	if err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+func (t *Retry) RoundTrip(req *http.Request) (
+	resp *http.Response, err error) {
+
+	rewindable := req.Body == nil || req.Body == http.NoBody ||
+		req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			attemptReq = new(http.Request)
+			*attemptReq = *req
+			attemptReq.Body, err = req.GetBody()
+			if err != nil {
+				return
+			}
+		}
+
+		resp, err = t.Transport.RoundTrip(attemptReq)
+
+		if attempt >= t.MaxRetries || !rewindable ||
+			!t.retryable(req, resp, err) {
+
+			return
+		}
+
+		wait := t.backoff(attempt, resp)
+
+		if err != nil {
+			log.Printf("[WARN] atlas: %s %s error %s, retrying in %s",
+				req.Method, req.URL.String(), err, wait)
+		} else {
+			log.Printf("[WARN] atlas: %s %s status %d, retrying in %s",
+				req.Method, req.URL.String(), resp.StatusCode, wait)
+
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			resp = nil
+			err = req.Context().Err()
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"", 0, 0},
+		{"invalid", 0, 0},
+		{"0", 0, 0},
+		{"5", 5 * time.Second, 5 * time.Second},
+		{
+			time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat),
+			8 * time.Second,
+			10 * time.Second,
+		},
+		{
+			time.Now().Add(-10 * time.Second).UTC().Format(http.TimeFormat),
+			-time.Minute,
+			0,
+		},
+	}
+
+	for _, test := range tests {
+		wait := retryAfter(test.header)
+		if wait < test.min || wait > test.max {
+			t.Errorf("retryAfter %q %s, expected %s to %s",
+				test.header, wait, test.min, test.max)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		maxWait    time.Duration
+		min        time.Duration
+		max        time.Duration
+	}{
+		{
+			name:    "first attempt",
+			attempt: 0,
+			maxWait: time.Minute,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "third attempt",
+			attempt: 2,
+			maxWait: time.Minute,
+			min:     200 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "capped attempt",
+			attempt: 20,
+			maxWait: time.Second,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		{
+			name:    "overflow attempt",
+			attempt: 70,
+			maxWait: time.Second,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		{
+			name:       "retry after",
+			attempt:    0,
+			retryAfter: "3",
+			maxWait:    time.Minute,
+			min:        3 * time.Second,
+			max:        3 * time.Second,
+		},
+		{
+			name:       "capped retry after",
+			attempt:    0,
+			retryAfter: "120",
+			maxWait:    time.Minute,
+			min:        time.Minute,
+			max:        time.Minute,
+		},
+		{
+			name:       "invalid retry after",
+			attempt:    0,
+			retryAfter: "invalid",
+			maxWait:    time.Minute,
+			min:        50 * time.Millisecond,
+			max:        100 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		rtry := &Retry{
+			MinWait: 100 * time.Millisecond,
+			MaxWait: test.maxWait,
+		}
+
+		resp := &http.Response{
+			Header: http.Header{},
+		}
+		if test.retryAfter != "" {
+			resp.Header.Set("Retry-After", test.retryAfter)
+		}
+
+		for i := 0; i < 20; i++ {
+			wait := rtry.backoff(test.attempt, resp)
+			if wait < test.min || wait > test.max {
+				t.Errorf("%s wait %s, expected %s to %s",
+					test.name, wait, test.min, test.max)
+				break
+			}
+		}
+	}
+}
+
+func TestRetryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int
+		requests int32
+		status   int
+	}{
+		{"get server error", "GET", []int{503, 502, 200}, 3, 200},
+		{"get exhausted", "GET", []int{500, 500, 500, 500}, 3, 500},
+		{"get not found", "GET", []int{404, 200}, 1, 404},
+		{"put server error", "PUT", []int{504, 200}, 2, 200},
+		{"post server error", "POST", []int{500, 200}, 1, 500},
+		{"post rate limited", "POST", []int{429, 429, 201}, 3, 201},
+	}
+
+	for _, test := range tests {
+		var count int32
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&count, 1)
+
+				body, _ := ioutil.ReadAll(r.Body)
+				if r.Method == "POST" && string(body) != "body" {
+					t.Errorf("%s request %d body %q, expected body",
+						test.name, n, body)
+				}
+
+				w.WriteHeader(test.statuses[n-1])
+			},
+		))
+
+		clnt := &http.Client{
+			Transport: &Retry{
+				Transport:  http.DefaultTransport,
+				MaxRetries: 2,
+				MinWait:    time.Millisecond,
+				MaxWait:    10 * time.Millisecond,
+			},
+		}
+
+		req, err := http.NewRequest(test.method, srv.URL,
+			bytes.NewReader([]byte("body")))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := clnt.Do(req)
+		if err != nil {
+			t.Errorf("%s error %s", test.name, err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode != test.status {
+				t.Errorf("%s status %d, expected %d",
+					test.name, resp.StatusCode, test.status)
+			}
+		}
+
+		if count != test.requests {
+			t.Errorf("%s sent %d requests, expected %d",
+				test.name, count, test.requests)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestRetryCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(503)
+		},
+	))
+	defer srv.Close()
+
+	clnt := &http.Client{
+		Transport: &Retry{
+			Transport:  http.DefaultTransport,
+			MaxRetries: 5,
+			MinWait:    time.Minute,
+			MaxWait:    time.Minute,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = clnt.Do(req.WithContext(ctx))
+	if err == nil {
+		t.Error("request expected context error")
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("request returned after %s", time.Since(start))
+	}
+}